@@ -0,0 +1,214 @@
+// Command agent is a standalone worker that pulls jobs from the orchestrator
+// server's gRPC agent pool and executes them, instead of the API server
+// running jobs in an in-process goroutine. Multiple agents can run this
+// binary against the same server to scale job execution horizontally.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"fintech-ai-platform/go-service/config"
+	"fintech-ai-platform/go-service/pkg/runner"
+	agentv1 "fintech-ai-platform/go-service/proto/agent/v1"
+)
+
+var logger = logrus.New()
+
+func main() {
+	serverAddr := flag.String("server", "localhost:9003", "orchestrator agent-pool gRPC address")
+	concurrency := flag.Int("concurrency", 4, "number of jobs this agent runs at once")
+	agentID := flag.String("id", "", "agent id reported to the server (defaults to a random uuid)")
+	flag.Parse()
+
+	id := *agentID
+	if id == "" {
+		id = uuid.New().String()
+	}
+	logger.WithField("agent_id", id).Info("starting agent")
+
+	conn, err := grpc.Dial(*serverAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		logger.Fatalf("failed to dial %s: %v", *serverAddr, err)
+	}
+	defer conn.Close()
+
+	client := agentv1.NewAgentServiceClient(conn)
+	cfg := config.LoadConfig()
+	run := runner.New(cfg)
+
+	w := &worker{
+		id:     id,
+		client: client,
+		runner: run,
+	}
+
+	go w.heartbeatLoop(context.Background(), time.Duration(cfg.Agent.LeaseDurationMS/2)*time.Millisecond)
+
+	if err := w.acquireLoop(context.Background(), *concurrency); err != nil {
+		logger.Fatalf("agent stopped: %v", err)
+	}
+}
+
+// worker holds the set of jobs this agent currently has leased, so the
+// heartbeat loop knows which IDs to renew.
+type worker struct {
+	id     string
+	client agentv1.AgentServiceClient
+	runner *runner.Runner
+
+	mu   sync.Mutex
+	jobs map[string]struct{}
+}
+
+func (w *worker) trackJob(jobID string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.jobs == nil {
+		w.jobs = make(map[string]struct{})
+	}
+	w.jobs[jobID] = struct{}{}
+}
+
+func (w *worker) untrackJob(jobID string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	delete(w.jobs, jobID)
+}
+
+func (w *worker) activeJobIDs() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	ids := make([]string, 0, len(w.jobs))
+	for id := range w.jobs {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// acquireLoop keeps `concurrency` AcquireJob requests in flight on a single
+// stream: as soon as a slot's job finishes, it sends another request so the
+// agent is always long-polling for up to `concurrency` jobs at once.
+func (w *worker) acquireLoop(ctx context.Context, concurrency int) error {
+	stream, err := w.client.AcquireJob(ctx)
+	if err != nil {
+		return err
+	}
+
+	slots := make(chan struct{}, concurrency)
+	for i := 0; i < concurrency; i++ {
+		slots <- struct{}{}
+	}
+
+	go func() {
+		for range slots {
+			if err := stream.Send(&agentv1.AcquireJobRequest{AgentId: w.id}); err != nil {
+				logger.WithError(err).Error("failed to send acquire request")
+				return
+			}
+		}
+	}()
+
+	for {
+		job, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+
+		go func() {
+			w.runJob(ctx, job)
+			slots <- struct{}{}
+		}()
+	}
+}
+
+func (w *worker) runJob(ctx context.Context, job *agentv1.AcquireJobResponse) {
+	log := logger.WithFields(logrus.Fields{"job_id": job.JobId, "job_type": job.JobType})
+	w.trackJob(job.JobId)
+	defer w.untrackJob(job.JobId)
+
+	var input map[string]interface{}
+	if job.Input != "" {
+		if err := json.Unmarshal([]byte(job.Input), &input); err != nil {
+			w.fail(ctx, job.JobId, err)
+			return
+		}
+	}
+
+	var (
+		result map[string]interface{}
+		err    error
+	)
+	switch job.JobType {
+	case "ml_prediction":
+		result, err = w.runner.ExecuteMLPrediction(ctx, job.Config, input)
+	case "ocr_extraction":
+		result, err = w.runner.ExecuteOCRExtraction(ctx, job.Config, input)
+	case "api_call":
+		result, err = w.runner.ExecuteAPICall(ctx, job.Config, input)
+	default:
+		log.Warn("unsupported job type, completing with no-op result")
+		result = input
+	}
+
+	if err != nil {
+		w.fail(ctx, job.JobId, err)
+		return
+	}
+
+	resultJSON, err := json.Marshal(result)
+	if err != nil {
+		w.fail(ctx, job.JobId, err)
+		return
+	}
+
+	if _, err := w.client.CompleteJob(ctx, &agentv1.CompleteJobRequest{
+		JobId:   job.JobId,
+		AgentId: w.id,
+		Result:  string(resultJSON),
+	}); err != nil {
+		log.WithError(err).Error("failed to report job completion")
+	}
+}
+
+func (w *worker) fail(ctx context.Context, jobID string, jobErr error) {
+	logger.WithField("job_id", jobID).WithError(jobErr).Error("job failed")
+	if _, err := w.client.FailJob(ctx, &agentv1.FailJobRequest{
+		JobId:   jobID,
+		AgentId: w.id,
+		Error:   jobErr.Error(),
+	}); err != nil {
+		logger.WithError(err).Error("failed to report job failure")
+	}
+}
+
+func (w *worker) heartbeatLoop(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			ids := w.activeJobIDs()
+			if len(ids) == 0 {
+				continue
+			}
+			if _, err := w.client.Heartbeat(ctx, &agentv1.HeartbeatRequest{
+				AgentId: w.id,
+				JobIds:  ids,
+			}); err != nil {
+				logger.WithError(err).Error("heartbeat failed")
+			}
+		}
+	}
+}
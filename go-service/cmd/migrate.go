@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"fintech-ai-platform/go-service/db"
+	"fintech-ai-platform/go-service/models"
+)
+
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Run GORM auto-migrations for workflows, tasks, and jobs",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := loadConfig()
+		logger := logrus.New()
+
+		conn, err := db.New(cfg)
+		if err != nil {
+			return err
+		}
+
+		logger.Info("running auto-migrations")
+		if err := conn.AutoMigrate(
+			&models.Workflow{},
+			&models.WorkflowStep{},
+			&models.WorkflowExecution{},
+			&models.WorkflowStepExecution{},
+			&models.Task{},
+			&models.TaskExecution{},
+			&models.Job{},
+			&models.JobExecution{},
+			&models.ExecutionLogLine{},
+			&models.APIKey{},
+			&models.APIKeyLimit{},
+		); err != nil {
+			return fmt.Errorf("auto-migrate: %w", err)
+		}
+
+		logger.Info("migrations complete")
+		return nil
+	},
+}
@@ -0,0 +1,40 @@
+// Package cmd implements the go-service CLI. serve runs the HTTP API,
+// worker runs the gRPC agent-pool server and lease reaper without it (so
+// the two can scale as separate Kubernetes deployments off the same
+// image), migrate applies GORM auto-migrations, and version prints the
+// build version.
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"fintech-ai-platform/go-service/config"
+)
+
+var configFile string
+
+var rootCmd = &cobra.Command{
+	Use:   "go-service",
+	Short: "Fintech workflow/task/job orchestration service",
+}
+
+// Execute runs the subcommand selected on the command line; it's the only
+// thing main() calls.
+func Execute() error {
+	return rootCmd.Execute()
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&configFile, "config", "", "config file (default: ./config.yaml or ./config/config.yaml)")
+	rootCmd.AddCommand(serveCmd, workerCmd, migrateCmd, versionCmd)
+}
+
+// loadConfig applies --config, if set, before delegating to
+// config.LoadConfig, so every subcommand resolves configuration the same
+// way regardless of which one is running.
+func loadConfig() *config.Config {
+	if configFile != "" {
+		config.SetConfigFile(configFile)
+	}
+	return config.LoadConfig()
+}
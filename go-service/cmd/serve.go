@@ -0,0 +1,216 @@
+package cmd
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.uber.org/fx"
+
+	"fintech-ai-platform/go-service/config"
+	"fintech-ai-platform/go-service/db"
+	"fintech-ai-platform/go-service/handlers"
+	"fintech-ai-platform/go-service/middleware"
+	"fintech-ai-platform/go-service/models"
+	"fintech-ai-platform/go-service/services"
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Run the HTTP API server",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if configFile != "" {
+			config.SetConfigFile(configFile)
+		}
+
+		fx.New(
+			config.Module,
+			db.Module,
+			services.Module,
+			middleware.Module,
+			fx.Provide(newLogger, newTracerProvider, newRouter),
+			fx.Invoke(registerRoutes, runHTTPServer),
+			fx.NopLogger,
+		).Run()
+		return nil
+	},
+}
+
+func newLogger() *logrus.Logger {
+	return logrus.New()
+}
+
+// newTracerProvider dials an OTLP collector over gRPC and registers an
+// fx.Lifecycle hook so the container flushes it on shutdown instead of
+// requiring every caller to remember a defer. With cfg.OTel.Endpoint left
+// unset, otlptracegrpc falls back to the standard OTEL_EXPORTER_OTLP_*
+// env vars, so the same binary can ship traces to Jaeger, Tempo, or any
+// other OTLP collector without a code change.
+func newTracerProvider(cfg *config.Config, lc fx.Lifecycle, logger *logrus.Logger) (*sdktrace.TracerProvider, error) {
+	ctx := context.Background()
+
+	opts := []otlptracegrpc.Option{}
+	if cfg.OTel.Endpoint != "" {
+		opts = append(opts, otlptracegrpc.WithEndpoint(cfg.OTel.Endpoint))
+	}
+	if cfg.OTel.Insecure {
+		opts = append(opts, otlptracegrpc.WithInsecure())
+	}
+
+	exp, err := otlptrace.New(ctx, otlptracegrpc.NewClient(opts...))
+	if err != nil {
+		return nil, err
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exp),
+		sdktrace.WithResource(resource.NewWithAttributes(
+			semconv.SchemaURL,
+			semconv.ServiceName("go-service"),
+		)),
+	)
+	otel.SetTracerProvider(tp)
+
+	lc.Append(fx.Hook{
+		OnStop: func(ctx context.Context) error {
+			if err := tp.Shutdown(ctx); err != nil {
+				logger.Printf("error shutting down tracer provider: %v", err)
+			}
+			return nil
+		},
+	})
+	return tp, nil
+}
+
+func newRouter(logger *logrus.Logger, rateLimiter *middleware.RateLimiter) *gin.Engine {
+	gin.SetMode(gin.ReleaseMode)
+	router := gin.New()
+	router.Use(gin.Recovery())
+	router.Use(otelgin.Middleware("go-service"))
+	router.Use(middleware.Logger(logger))
+	router.Use(middleware.CORS())
+	router.Use(middleware.Metrics())
+	router.Use(middleware.TraceAttributes())
+	router.Use(rateLimiter.Middleware())
+	return router
+}
+
+// registerRoutes is an fx.Invoke target: it exists purely to pull the
+// router and every service the route table needs out of the container and
+// wire them together via setupRoutes.
+func registerRoutes(router *gin.Engine, orchestratorService services.Orchestrator, workflowService services.Workflow, logService *services.LogService, authService *services.AuthService) {
+	setupRoutes(router, orchestratorService, workflowService, logService, authService)
+}
+
+// runHTTPServer starts the HTTP server on OnStart and drains in-flight
+// requests on OnStop, for as long as the stop context fx passes in allows;
+// fx.App.Run already handles the SIGINT/SIGTERM wait and invokes these hooks
+// in order.
+func runHTTPServer(lc fx.Lifecycle, router *gin.Engine, cfg *config.Config, logger *logrus.Logger, tp *sdktrace.TracerProvider) {
+	srv := &http.Server{
+		Addr:    ":" + cfg.Server.Port,
+		Handler: router,
+	}
+
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			go func() {
+				logger.Info("Starting Go Service on port", cfg.Server.Port)
+				if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+					logger.Fatalf("Failed to start server: %v", err)
+				}
+			}()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			logger.Info("Shutting down server...")
+			return srv.Shutdown(ctx)
+		},
+	})
+}
+
+func setupRoutes(router *gin.Engine, orchestratorService services.Orchestrator, workflowService services.Workflow, logService *services.LogService, authService *services.AuthService) {
+	// Health check
+	router.GET("/health", handlers.HealthCheck)
+
+	// Prometheus scrape endpoint: unauthenticated like /health, since it's
+	// meant to be reachable by an in-cluster scraper, not API callers.
+	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
+
+	// API v1 routes. Every route below requires a valid JWT or API key;
+	// middleware.RequireScope additionally gates it on the scope the route
+	// needs (see models.Principal and the Scope* constants).
+	v1 := router.Group("/api/v1")
+	v1.Use(middleware.Auth(authService))
+	{
+		// Auth routes: API key issuance/rotation/revocation, gated on
+		// auth:admin so only operators can mint credentials.
+		authKeys := v1.Group("/auth/keys")
+		authKeys.Use(middleware.RequireScope(models.ScopeAuthAdmin))
+		{
+			authKeys.POST("/", handlers.CreateAPIKey(authService))
+			authKeys.GET("/", handlers.ListAPIKeys(authService))
+			authKeys.POST("/:id/rotate", handlers.RotateAPIKey(authService))
+			authKeys.DELETE("/:id", handlers.RevokeAPIKey(authService))
+			authKeys.PUT("/:id/limit", handlers.SetAPIKeyLimit(authService))
+			authKeys.DELETE("/:id/limit", handlers.DeleteAPIKeyLimit(authService))
+		}
+
+		// Workflow routes
+		workflows := v1.Group("/workflows")
+		{
+			workflows.POST("/", middleware.RequireScope(models.ScopeWorkflowWrite), handlers.CreateWorkflow(workflowService))
+			workflows.GET("/", middleware.RequireScope(models.ScopeWorkflowRead), handlers.ListWorkflows(workflowService))
+			workflows.GET("/:id", middleware.RequireScope(models.ScopeWorkflowRead), handlers.GetWorkflow(workflowService))
+			workflows.PUT("/:id", middleware.RequireScope(models.ScopeWorkflowWrite), handlers.UpdateWorkflow(workflowService))
+			workflows.DELETE("/:id", middleware.RequireScope(models.ScopeWorkflowWrite), handlers.DeleteWorkflow(workflowService))
+			workflows.POST("/:id/execute", middleware.RequireScope(models.ScopeWorkflowExecute), handlers.ExecuteWorkflow(workflowService))
+			workflows.POST("/:id/dispatch", middleware.RequireScope(models.ScopeWorkflowExecute), handlers.DispatchWorkflow(workflowService))
+			workflows.GET("/:id/executions", middleware.RequireScope(models.ScopeWorkflowRead), handlers.ListWorkflowExecutions(workflowService))
+			workflows.GET("/:id/executions/:execId/steps", middleware.RequireScope(models.ScopeWorkflowRead), handlers.ListWorkflowExecutionSteps(workflowService))
+			workflows.GET("/:id/executions/:execId/logs", middleware.RequireScope(models.ScopeWorkflowRead), handlers.GetWorkflowExecutionLogs(workflowService, logService))
+		}
+
+		// Orchestration routes
+		orchestration := v1.Group("/orchestration")
+		{
+			orchestration.POST("/tasks", middleware.RequireScope(models.ScopeJobWrite), handlers.CreateTask(orchestratorService))
+			orchestration.GET("/tasks", middleware.RequireScope(models.ScopeJobRead), handlers.ListTasks(orchestratorService))
+			orchestration.GET("/tasks/:id", middleware.RequireScope(models.ScopeJobRead), handlers.GetTask(orchestratorService))
+			orchestration.PUT("/tasks/:id", middleware.RequireScope(models.ScopeJobWrite), handlers.UpdateTask(orchestratorService))
+			orchestration.DELETE("/tasks/:id", middleware.RequireScope(models.ScopeJobWrite), handlers.DeleteTask(orchestratorService))
+			orchestration.POST("/tasks/:id/execute", middleware.RequireScope(models.ScopeJobAdmin), handlers.ExecuteTask(orchestratorService))
+			orchestration.POST("/tasks/:id/stop", middleware.RequireScope(models.ScopeJobAdmin), handlers.StopTask(orchestratorService))
+			orchestration.GET("/tasks/:id/executions", middleware.RequireScope(models.ScopeJobRead), handlers.ListTaskExecutions(orchestratorService))
+			orchestration.GET("/tasks/:id/executions/:execId/logs", middleware.RequireScope(models.ScopeJobRead), handlers.GetTaskExecutionLogs(orchestratorService, logService))
+		}
+
+		// Job routes
+		jobs := v1.Group("/jobs")
+		{
+			jobs.POST("/", middleware.RequireScope(models.ScopeJobWrite), handlers.CreateJob(orchestratorService))
+			jobs.GET("/", middleware.RequireScope(models.ScopeJobRead), handlers.ListJobs(orchestratorService))
+			jobs.GET("/dead-letter", middleware.RequireScope(models.ScopeJobAdmin), handlers.ListDeadLetterJobs(orchestratorService))
+			jobs.GET("/:id", middleware.RequireScope(models.ScopeJobRead), handlers.GetJob(orchestratorService))
+			jobs.PUT("/:id", middleware.RequireScope(models.ScopeJobWrite), handlers.UpdateJob(orchestratorService))
+			jobs.DELETE("/:id", middleware.RequireScope(models.ScopeJobWrite), handlers.DeleteJob(orchestratorService))
+			jobs.POST("/:id/start", middleware.RequireScope(models.ScopeJobAdmin), handlers.StartJob(orchestratorService))
+			jobs.POST("/:id/stop", middleware.RequireScope(models.ScopeJobAdmin), handlers.StopJob(orchestratorService))
+			jobs.POST("/:id/requeue", middleware.RequireScope(models.ScopeJobAdmin), handlers.RequeueJob(orchestratorService))
+			jobs.GET("/:id/executions", middleware.RequireScope(models.ScopeJobRead), handlers.ListJobExecutions(orchestratorService))
+			jobs.GET("/:id/logs", middleware.RequireScope(models.ScopeJobRead), handlers.GetJobLogs(orchestratorService, logService))
+			jobs.GET("/:id/events", middleware.RequireScope(models.ScopeJobRead), handlers.GetJobEvents(orchestratorService, logService))
+			jobs.GET("/:id/ws", middleware.RequireScope(models.ScopeJobRead), handlers.GetJobWS(orchestratorService, logService))
+		}
+	}
+}
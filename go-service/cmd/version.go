@@ -0,0 +1,20 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// Version is overridden at build time via
+// -ldflags "-X fintech-ai-platform/go-service/cmd.Version=...".
+var Version = "dev"
+
+var versionCmd = &cobra.Command{
+	Use:   "version",
+	Short: "Print the go-service build version",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fmt.Println(Version)
+		return nil
+	},
+}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"context"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+
+	"fintech-ai-platform/go-service/db"
+	agentv1 "fintech-ai-platform/go-service/proto/agent/v1"
+	"fintech-ai-platform/go-service/services"
+)
+
+var workerCmd = &cobra.Command{
+	Use:   "worker",
+	Short: "Run the gRPC agent-pool server and lease reaper, without the HTTP API",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runWorker()
+	},
+}
+
+func runWorker() error {
+	cfg := loadConfig()
+	logger := logrus.New()
+
+	conn, err := db.New(cfg)
+	if err != nil {
+		return err
+	}
+
+	// logService backs the live log/progress streaming endpoints; the
+	// worker appends to it as agents report progress, and the serve
+	// process's HTTP handlers read it back.
+	logService := services.NewLogService(conn, &cfg.Redis)
+
+	agentServer := services.NewAgentServer(
+		time.Duration(cfg.Agent.PollIntervalMS)*time.Millisecond,
+		time.Duration(cfg.Agent.LeaseDurationMS)*time.Millisecond,
+		cfg.Agent.MaxAttempts,
+		time.Duration(cfg.Agent.InitialBackoffMS)*time.Millisecond,
+		time.Duration(cfg.Agent.MaxBackoffMS)*time.Millisecond,
+		conn,
+		logService,
+	)
+
+	reaperCtx, stopReaper := context.WithCancel(context.Background())
+	defer stopReaper()
+	go agentServer.RunReaper(reaperCtx, time.Duration(cfg.Agent.ReaperIntervalMS)*time.Millisecond)
+
+	grpcServer := grpc.NewServer()
+	agentv1.RegisterAgentServiceServer(grpcServer, agentServer)
+	lis, err := net.Listen("tcp", ":"+cfg.Agent.GRPCPort)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		logger.Infof("starting agent pool gRPC server on port %s", cfg.Agent.GRPCPort)
+		if err := grpcServer.Serve(lis); err != nil {
+			logger.Errorf("agent pool gRPC server stopped: %v", err)
+		}
+	}()
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+	logger.Info("shutting down worker...")
+	grpcServer.GracefulStop()
+	return nil
+}
@@ -1,15 +1,20 @@
 package config
 
 import (
+	"strings"
+
 	"github.com/spf13/viper"
 )
 
 type Config struct {
-	Server   ServerConfig   `mapstructure:"server"`
-	Database DatabaseConfig `mapstructure:"database"`
-	Redis    RedisConfig    `mapstructure:"redis"`
-	Jaeger   JaegerConfig   `mapstructure:"jaeger"`
-	Services ServicesConfig `mapstructure:"services"`
+	Server    ServerConfig    `mapstructure:"server"`
+	Database  DatabaseConfig  `mapstructure:"database"`
+	Redis     RedisConfig     `mapstructure:"redis"`
+	OTel      OTelConfig      `mapstructure:"otel"`
+	Services  ServicesConfig  `mapstructure:"services"`
+	Agent     AgentConfig     `mapstructure:"agent"`
+	RateLimit RateLimitConfig `mapstructure:"rate_limit"`
+	Auth      AuthConfig      `mapstructure:"auth"`
 }
 
 type ServerConfig struct {
@@ -33,8 +38,14 @@ type RedisConfig struct {
 	DB       int    `mapstructure:"db"`
 }
 
-type JaegerConfig struct {
+// OTelConfig configures the OTLP trace exporter cmd/serve's
+// newTracerProvider builds. Endpoint is left blank by default so the
+// exporter falls back to the standard OTEL_EXPORTER_OTLP_ENDPOINT (and
+// related OTEL_EXPORTER_OTLP_*) env vars instead of a hardcoded collector
+// address; set it here only to override that for a given deployment.
+type OTelConfig struct {
 	Endpoint string `mapstructure:"endpoint"`
+	Insecure bool   `mapstructure:"insecure"`
 }
 
 type ServicesConfig struct {
@@ -43,16 +54,70 @@ type ServicesConfig struct {
 	OCRService string `mapstructure:"ocr_service"`
 }
 
+// AgentConfig controls the gRPC agent pool: the server's AcquireJob long
+// poll and lease durations, the port cmd/agent workers dial, and the
+// retry/backoff policy AgentServer applies to a job a worker failed or
+// abandoned.
+type AgentConfig struct {
+	GRPCPort         string `mapstructure:"grpc_port"`
+	PollIntervalMS   int    `mapstructure:"poll_interval_ms"`
+	LeaseDurationMS  int    `mapstructure:"lease_duration_ms"`
+	ReaperIntervalMS int    `mapstructure:"reaper_interval_ms"`
+	// MaxAttempts is how many times a job may be claimed before it's moved
+	// to models.StatusDeadLetter instead of being re-enqueued.
+	MaxAttempts int `mapstructure:"max_attempts"`
+	// InitialBackoffMS/MaxBackoffMS bound the exponential backoff applied
+	// between attempts: attempt N waits min(InitialBackoffMS*2^(N-1), MaxBackoffMS).
+	InitialBackoffMS int `mapstructure:"initial_backoff_ms"`
+	MaxBackoffMS     int `mapstructure:"max_backoff_ms"`
+}
+
+// RateLimitConfig is the default token-bucket quota middleware.RateLimiter
+// applies to a caller with no per-key override in Postgres (see
+// models.APIKeyLimit).
+type RateLimitConfig struct {
+	RequestsPerSecond float64 `mapstructure:"requests_per_second"`
+	Burst             int     `mapstructure:"burst"`
+}
+
+// AuthConfig controls how middleware.Auth validates a JWT. Algorithm is
+// either "HS256" (Secret is the shared signing key) or "RS256" (PublicKey
+// is the PEM-encoded verification key; tokens are signed elsewhere).
+type AuthConfig struct {
+	JWTAlgorithm string `mapstructure:"jwt_algorithm"`
+	JWTSecret    string `mapstructure:"jwt_secret"`
+	JWTPublicKey string `mapstructure:"jwt_public_key"`
+	Issuer       string `mapstructure:"issuer"`
+}
+
+// configFile, set via SetConfigFile, overrides LoadConfig's default search
+// path. cmd's root command wires this up to its --config flag before any
+// subcommand calls LoadConfig.
+var configFile string
+
+// SetConfigFile points LoadConfig at an explicit file instead of searching
+// "." and "./config" for config.yaml.
+func SetConfigFile(path string) {
+	configFile = path
+}
+
 func LoadConfig() *Config {
-	viper.SetConfigName("config")
-	viper.SetConfigType("yaml")
-	viper.AddConfigPath(".")
-	viper.AddConfigPath("./config")
+	if configFile != "" {
+		viper.SetConfigFile(configFile)
+	} else {
+		viper.SetConfigName("config")
+		viper.SetConfigType("yaml")
+		viper.AddConfigPath(".")
+		viper.AddConfigPath("./config")
+	}
 
 	// Set defaults
 	setDefaults()
 
-	// Read environment variables
+	// Read environment variables, e.g. GOSVC_DATABASE_HOST overrides
+	// database.host.
+	viper.SetEnvPrefix("gosvc")
+	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
 	viper.AutomaticEnv()
 
 	// Read config file
@@ -87,11 +152,33 @@ func setDefaults() {
 	viper.SetDefault("redis.password", "")
 	viper.SetDefault("redis.db", 2)
 
-	// Jaeger defaults
-	viper.SetDefault("jaeger.endpoint", "http://jaeger:14268/api/traces")
+	// OTel defaults: endpoint is left empty so the OTLP exporter's own
+	// OTEL_EXPORTER_OTLP_ENDPOINT env var resolution takes over unless a
+	// deployment sets otel.endpoint explicitly.
+	viper.SetDefault("otel.endpoint", "")
+	viper.SetDefault("otel.insecure", true)
 
 	// Services defaults
 	viper.SetDefault("services.api_gateway", "http://api-gateway:8000")
 	viper.SetDefault("services.ml_service", "http://ml-service:8001")
 	viper.SetDefault("services.ocr_service", "http://ocr-service:8002")
+
+	// Agent pool defaults
+	viper.SetDefault("agent.grpc_port", "9003")
+	viper.SetDefault("agent.poll_interval_ms", 5000)
+	viper.SetDefault("agent.lease_duration_ms", 30000)
+	viper.SetDefault("agent.reaper_interval_ms", 10000)
+	viper.SetDefault("agent.max_attempts", 5)
+	viper.SetDefault("agent.initial_backoff_ms", 1000)
+	viper.SetDefault("agent.max_backoff_ms", 60000)
+
+	// Rate limit defaults
+	viper.SetDefault("rate_limit.requests_per_second", 10)
+	viper.SetDefault("rate_limit.burst", 20)
+
+	// Auth defaults
+	viper.SetDefault("auth.jwt_algorithm", "HS256")
+	viper.SetDefault("auth.jwt_secret", "")
+	viper.SetDefault("auth.jwt_public_key", "")
+	viper.SetDefault("auth.issuer", "fintech-ai-platform")
 }
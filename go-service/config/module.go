@@ -0,0 +1,6 @@
+package config
+
+import "go.uber.org/fx"
+
+// Module provides the parsed Config to the DI container.
+var Module = fx.Module("config", fx.Provide(LoadConfig))
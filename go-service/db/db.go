@@ -0,0 +1,30 @@
+// Package db opens the one Postgres connection every command shares:
+// cmd/serve wires it into the fx container for services/middleware,
+// cmd/worker and cmd/migrate (which aren't fx apps) call New directly.
+package db
+
+import (
+	"fmt"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/plugin/opentelemetry/tracing"
+
+	"fintech-ai-platform/go-service/config"
+)
+
+// New opens a gorm connection to cfg.Database and installs the OTel
+// tracing plugin so every query shows up as a span alongside the HTTP
+// request that triggered it.
+func New(cfg *config.Config) (*gorm.DB, error) {
+	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
+		cfg.Database.Host, cfg.Database.Port, cfg.Database.User, cfg.Database.Password, cfg.Database.DBName, cfg.Database.SSLMode)
+	conn, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	if err != nil {
+		return nil, fmt.Errorf("connect to database: %w", err)
+	}
+	if err := conn.Use(tracing.NewPlugin()); err != nil {
+		return nil, fmt.Errorf("install gorm tracing plugin: %w", err)
+	}
+	return conn, nil
+}
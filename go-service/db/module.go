@@ -0,0 +1,7 @@
+package db
+
+import "go.uber.org/fx"
+
+// Module provides the shared *gorm.DB every service and middleware
+// component in the fx container reads and writes through.
+var Module = fx.Module("db", fx.Provide(New))
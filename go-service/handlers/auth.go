@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"fintech-ai-platform/go-service/models"
+	"fintech-ai-platform/go-service/services"
+)
+
+// CreateAPIKey handles POST /api/v1/auth/keys. The response's Key field is
+// the only time the plaintext key is ever returned; only its hash is kept.
+func CreateAPIKey(authService *services.AuthService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req models.CreateAPIKeyRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		key, err := authService.CreateAPIKey(req)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusCreated, key)
+	}
+}
+
+// ListAPIKeys handles GET /api/v1/auth/keys.
+func ListAPIKeys(authService *services.AuthService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		keys, err := authService.ListAPIKeys()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, keys)
+	}
+}
+
+// RotateAPIKey handles POST /api/v1/auth/keys/{id}/rotate: it revokes the
+// existing key and returns a freshly issued one with the same owner/scopes.
+func RotateAPIKey(authService *services.AuthService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid key ID"})
+			return
+		}
+
+		key, err := authService.RotateAPIKey(id)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "API key not found"})
+			return
+		}
+
+		c.JSON(http.StatusOK, key)
+	}
+}
+
+// RevokeAPIKey handles DELETE /api/v1/auth/keys/{id}.
+func RevokeAPIKey(authService *services.AuthService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid key ID"})
+			return
+		}
+
+		if err := authService.RevokeAPIKey(id); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"status": "revoked"})
+	}
+}
+
+// SetAPIKeyLimit handles PUT /api/v1/auth/keys/{id}/limit: it creates or
+// updates the rate-limit override middleware.RateLimiter applies to id's key.
+func SetAPIKeyLimit(authService *services.AuthService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid key ID"})
+			return
+		}
+
+		var req models.SetAPIKeyLimitRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		limit, err := authService.SetAPIKeyLimit(id, req.RequestsPerSecond, req.Burst)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "API key not found"})
+			return
+		}
+
+		c.JSON(http.StatusOK, limit)
+	}
+}
+
+// DeleteAPIKeyLimit handles DELETE /api/v1/auth/keys/{id}/limit: it removes
+// id's rate-limit override, if any, so it falls back to the config default.
+func DeleteAPIKeyLimit(authService *services.AuthService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid key ID"})
+			return
+		}
+
+		if err := authService.DeleteAPIKeyLimit(id); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "API key not found"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"status": "deleted"})
+	}
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -21,7 +22,7 @@ func HealthCheck(c *gin.Context) {
 
 // Workflow handlers
 
-func CreateWorkflow(workflowService *services.WorkflowService) gin.HandlerFunc {
+func CreateWorkflow(workflowService services.Workflow) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var req models.CreateWorkflowRequest
 		if err := c.ShouldBindJSON(&req); err != nil {
@@ -31,6 +32,10 @@ func CreateWorkflow(workflowService *services.WorkflowService) gin.HandlerFunc {
 
 		workflow, err := workflowService.CreateWorkflow(req)
 		if err != nil {
+			if errors.Is(err, services.ErrInvalidWorkflow) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
 		}
@@ -39,7 +44,7 @@ func CreateWorkflow(workflowService *services.WorkflowService) gin.HandlerFunc {
 	}
 }
 
-func ListWorkflows(workflowService *services.WorkflowService) gin.HandlerFunc {
+func ListWorkflows(workflowService services.Workflow) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 		limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
@@ -59,7 +64,7 @@ func ListWorkflows(workflowService *services.WorkflowService) gin.HandlerFunc {
 	}
 }
 
-func GetWorkflow(workflowService *services.WorkflowService) gin.HandlerFunc {
+func GetWorkflow(workflowService services.Workflow) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		id, err := uuid.Parse(c.Param("id"))
 		if err != nil {
@@ -77,7 +82,7 @@ func GetWorkflow(workflowService *services.WorkflowService) gin.HandlerFunc {
 	}
 }
 
-func UpdateWorkflow(workflowService *services.WorkflowService) gin.HandlerFunc {
+func UpdateWorkflow(workflowService services.Workflow) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		id, err := uuid.Parse(c.Param("id"))
 		if err != nil {
@@ -93,6 +98,10 @@ func UpdateWorkflow(workflowService *services.WorkflowService) gin.HandlerFunc {
 
 		workflow, err := workflowService.UpdateWorkflow(id, req)
 		if err != nil {
+			if errors.Is(err, services.ErrInvalidWorkflow) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
 		}
@@ -101,7 +110,7 @@ func UpdateWorkflow(workflowService *services.WorkflowService) gin.HandlerFunc {
 	}
 }
 
-func DeleteWorkflow(workflowService *services.WorkflowService) gin.HandlerFunc {
+func DeleteWorkflow(workflowService services.Workflow) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		id, err := uuid.Parse(c.Param("id"))
 		if err != nil {
@@ -119,7 +128,7 @@ func DeleteWorkflow(workflowService *services.WorkflowService) gin.HandlerFunc {
 	}
 }
 
-func ExecuteWorkflow(workflowService *services.WorkflowService) gin.HandlerFunc {
+func ExecuteWorkflow(workflowService services.Workflow) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		id, err := uuid.Parse(c.Param("id"))
 		if err != nil {
@@ -133,7 +142,7 @@ func ExecuteWorkflow(workflowService *services.WorkflowService) gin.HandlerFunc
 			return
 		}
 
-		result, err := workflowService.ExecuteWorkflow(id, req.Input)
+		result, err := workflowService.ExecuteWorkflow(c.Request.Context(), id, req.Input)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
@@ -143,9 +152,99 @@ func ExecuteWorkflow(workflowService *services.WorkflowService) gin.HandlerFunc
 	}
 }
 
+// DispatchWorkflow handles manual-trigger runs: the request body is the
+// workflow's declared inputs (not wrapped in an "input" field like
+// ExecuteWorkflow), validated against the workflow's InputSchema before the
+// run starts.
+func DispatchWorkflow(workflowService services.Workflow) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid workflow ID"})
+			return
+		}
+
+		var input map[string]interface{}
+		if c.Request.ContentLength != 0 {
+			if err := c.ShouldBindJSON(&input); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+		}
+
+		result, err := workflowService.DispatchWorkflow(c.Request.Context(), id, input)
+		if err != nil {
+			if errors.Is(err, services.ErrDispatchInput) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, result)
+	}
+}
+
+func ListWorkflowExecutions(workflowService services.Workflow) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid workflow ID"})
+			return
+		}
+
+		page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+		limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+		executions, total, err := workflowService.ListWorkflowExecutions(id, page, limit)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"executions": executions,
+			"total":      total,
+			"page":       page,
+			"limit":      limit,
+		})
+	}
+}
+
+func ListWorkflowExecutionSteps(workflowService services.Workflow) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, err := uuid.Parse(c.Param("id")); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid workflow ID"})
+			return
+		}
+		execID, err := uuid.Parse(c.Param("execId"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid execution ID"})
+			return
+		}
+
+		page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+		limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+		steps, total, err := workflowService.ListWorkflowExecutionSteps(execID, page, limit)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"steps": steps,
+			"total": total,
+			"page":  page,
+			"limit": limit,
+		})
+	}
+}
+
 // Task handlers
 
-func CreateTask(orchestratorService *services.OrchestratorService) gin.HandlerFunc {
+func CreateTask(orchestratorService services.Orchestrator) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var req models.CreateTaskRequest
 		if err := c.ShouldBindJSON(&req); err != nil {
@@ -163,7 +262,7 @@ func CreateTask(orchestratorService *services.OrchestratorService) gin.HandlerFu
 	}
 }
 
-func ListTasks(orchestratorService *services.OrchestratorService) gin.HandlerFunc {
+func ListTasks(orchestratorService services.Orchestrator) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 		limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
@@ -183,7 +282,7 @@ func ListTasks(orchestratorService *services.OrchestratorService) gin.HandlerFun
 	}
 }
 
-func GetTask(orchestratorService *services.OrchestratorService) gin.HandlerFunc {
+func GetTask(orchestratorService services.Orchestrator) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		id, err := uuid.Parse(c.Param("id"))
 		if err != nil {
@@ -201,7 +300,7 @@ func GetTask(orchestratorService *services.OrchestratorService) gin.HandlerFunc
 	}
 }
 
-func UpdateTask(orchestratorService *services.OrchestratorService) gin.HandlerFunc {
+func UpdateTask(orchestratorService services.Orchestrator) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		id, err := uuid.Parse(c.Param("id"))
 		if err != nil {
@@ -225,7 +324,7 @@ func UpdateTask(orchestratorService *services.OrchestratorService) gin.HandlerFu
 	}
 }
 
-func DeleteTask(orchestratorService *services.OrchestratorService) gin.HandlerFunc {
+func DeleteTask(orchestratorService services.Orchestrator) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		id, err := uuid.Parse(c.Param("id"))
 		if err != nil {
@@ -243,7 +342,7 @@ func DeleteTask(orchestratorService *services.OrchestratorService) gin.HandlerFu
 	}
 }
 
-func ExecuteTask(orchestratorService *services.OrchestratorService) gin.HandlerFunc {
+func ExecuteTask(orchestratorService services.Orchestrator) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		id, err := uuid.Parse(c.Param("id"))
 		if err != nil {
@@ -257,7 +356,7 @@ func ExecuteTask(orchestratorService *services.OrchestratorService) gin.HandlerF
 			return
 		}
 
-		result, err := orchestratorService.ExecuteTask(id, req.Input)
+		result, err := orchestratorService.ExecuteTask(c.Request.Context(), id, req.Input)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
@@ -267,9 +366,52 @@ func ExecuteTask(orchestratorService *services.OrchestratorService) gin.HandlerF
 	}
 }
 
+func StopTask(orchestratorService services.Orchestrator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid task ID"})
+			return
+		}
+
+		if err := orchestratorService.StopTask(id); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Task stopped successfully"})
+	}
+}
+
+func ListTaskExecutions(orchestratorService services.Orchestrator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid task ID"})
+			return
+		}
+
+		page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+		limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+		executions, total, err := orchestratorService.ListTaskExecutions(id, page, limit)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"executions": executions,
+			"total":      total,
+			"page":       page,
+			"limit":      limit,
+		})
+	}
+}
+
 // Job handlers
 
-func CreateJob(orchestratorService *services.OrchestratorService) gin.HandlerFunc {
+func CreateJob(orchestratorService services.Orchestrator) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var req models.CreateJobRequest
 		if err := c.ShouldBindJSON(&req); err != nil {
@@ -287,7 +429,7 @@ func CreateJob(orchestratorService *services.OrchestratorService) gin.HandlerFun
 	}
 }
 
-func ListJobs(orchestratorService *services.OrchestratorService) gin.HandlerFunc {
+func ListJobs(orchestratorService services.Orchestrator) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 		limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
@@ -307,7 +449,7 @@ func ListJobs(orchestratorService *services.OrchestratorService) gin.HandlerFunc
 	}
 }
 
-func GetJob(orchestratorService *services.OrchestratorService) gin.HandlerFunc {
+func GetJob(orchestratorService services.Orchestrator) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		id, err := uuid.Parse(c.Param("id"))
 		if err != nil {
@@ -325,7 +467,7 @@ func GetJob(orchestratorService *services.OrchestratorService) gin.HandlerFunc {
 	}
 }
 
-func UpdateJob(orchestratorService *services.OrchestratorService) gin.HandlerFunc {
+func UpdateJob(orchestratorService services.Orchestrator) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		id, err := uuid.Parse(c.Param("id"))
 		if err != nil {
@@ -349,7 +491,7 @@ func UpdateJob(orchestratorService *services.OrchestratorService) gin.HandlerFun
 	}
 }
 
-func DeleteJob(orchestratorService *services.OrchestratorService) gin.HandlerFunc {
+func DeleteJob(orchestratorService services.Orchestrator) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		id, err := uuid.Parse(c.Param("id"))
 		if err != nil {
@@ -367,7 +509,33 @@ func DeleteJob(orchestratorService *services.OrchestratorService) gin.HandlerFun
 	}
 }
 
-func StartJob(orchestratorService *services.OrchestratorService) gin.HandlerFunc {
+func ListJobExecutions(orchestratorService services.Orchestrator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid job ID"})
+			return
+		}
+
+		page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+		limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+		executions, total, err := orchestratorService.ListJobExecutions(id, page, limit)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"executions": executions,
+			"total":      total,
+			"page":       page,
+			"limit":      limit,
+		})
+	}
+}
+
+func StartJob(orchestratorService services.Orchestrator) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		id, err := uuid.Parse(c.Param("id"))
 		if err != nil {
@@ -385,7 +553,7 @@ func StartJob(orchestratorService *services.OrchestratorService) gin.HandlerFunc
 	}
 }
 
-func StopJob(orchestratorService *services.OrchestratorService) gin.HandlerFunc {
+func StopJob(orchestratorService services.Orchestrator) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		id, err := uuid.Parse(c.Param("id"))
 		if err != nil {
@@ -402,3 +570,44 @@ func StopJob(orchestratorService *services.OrchestratorService) gin.HandlerFunc
 		c.JSON(http.StatusOK, gin.H{"message": "Job stopped successfully"})
 	}
 }
+
+// ListDeadLetterJobs lists jobs that exhausted AgentServer's retry budget:
+// GET /jobs/dead-letter.
+func ListDeadLetterJobs(orchestratorService services.Orchestrator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+		limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+		jobs, total, err := orchestratorService.ListDeadLetterJobs(page, limit)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"jobs":  jobs,
+			"total": total,
+			"page":  page,
+			"limit": limit,
+		})
+	}
+}
+
+// RequeueJob gives a dead-lettered job a fresh attempt budget and puts it
+// back in the claimable pool: POST /jobs/{id}/requeue.
+func RequeueJob(orchestratorService services.Orchestrator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid job ID"})
+			return
+		}
+
+		if err := orchestratorService.RequeueDeadLetterJob(id); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Job requeued successfully"})
+	}
+}
@@ -0,0 +1,338 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+
+	"fintech-ai-platform/go-service/models"
+	"fintech-ai-platform/go-service/services"
+)
+
+// defaultLogLines is how many backlog lines GET .../logs returns when the
+// caller omits ?lines=N.
+const defaultLogLines = 100
+
+func parseLogLines(c *gin.Context) int {
+	n, err := strconv.Atoi(c.DefaultQuery("lines", strconv.Itoa(defaultLogLines)))
+	if err != nil || n <= 0 {
+		return defaultLogLines
+	}
+	return n
+}
+
+func isTerminalStatus(status string) bool {
+	switch status {
+	case models.StatusCompleted, models.StatusFailed, models.StatusCancelled, models.StatusDeadLetter:
+		return true
+	default:
+		return false
+	}
+}
+
+// streamExecutionLogs writes an execution's backlog as Server-Sent Events,
+// then, if follow is set and the execution hasn't already reached a
+// terminal status, forwards new lines published to its Redis channel until
+// a terminal line arrives or the client disconnects. There's no
+// http.CloseNotifier in modern net/http; request.Context().Done() is its
+// replacement and is what actually unblocks the Subscribe loop below.
+func streamExecutionLogs(c *gin.Context, logService *services.LogService, executionID uuid.UUID, follow, alreadyTerminal bool) {
+	backlog, err := logService.TailLogs(executionID, parseLogLines(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Writer.Header().Set("Content-Type", "text/event-stream")
+	c.Writer.Header().Set("Cache-Control", "no-cache")
+	c.Writer.Header().Set("Connection", "keep-alive")
+	c.Writer.WriteHeader(http.StatusOK)
+	flusher, canFlush := c.Writer.(http.Flusher)
+
+	for _, line := range backlog {
+		fmt.Fprintf(c.Writer, "data: %s\n\n", line.Message)
+	}
+	if canFlush {
+		flusher.Flush()
+	}
+
+	if !follow || alreadyTerminal {
+		return
+	}
+
+	ctx := c.Request.Context()
+	sub := logService.Subscribe(ctx, executionID)
+	defer sub.Close()
+
+	ch := sub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-ch:
+			if !ok {
+				return
+			}
+			var parsed services.LogMessage
+			if err := json.Unmarshal([]byte(msg.Payload), &parsed); err != nil {
+				continue
+			}
+			fmt.Fprintf(c.Writer, "data: %s\n\n", parsed.Line.Message)
+			if canFlush {
+				flusher.Flush()
+			}
+			if parsed.Terminal {
+				return
+			}
+		}
+	}
+}
+
+// jobEvent is the structured message GetJobEvents/GetJobWS emit, as opposed
+// to the plain-text SSE frames streamExecutionLogs writes for .../logs.
+// Type is "status" for a job-level status/progress snapshot or "log" for a
+// single execution log line.
+type jobEvent struct {
+	Type     string `json:"type"`
+	Status   string `json:"status,omitempty"`
+	Progress int    `json:"progress,omitempty"`
+	Message  string `json:"message,omitempty"`
+}
+
+// streamJobEvents writes a job's current status, then its most recent
+// execution's log backlog, then follows new log lines and status
+// transitions until the job reaches a terminal status or the client
+// disconnects. Unlike streamExecutionLogs, this resolves the job's current
+// execution on every retry: a job that fails and is re-enqueued opens a new
+// JobExecution (and a new Redis channel), so following by job ID instead of
+// a fixed execution ID is what lets a caller hold one connection across
+// retries. send is called for every event; it returns false to stop (the
+// client disconnected or the underlying transport errored).
+func streamJobEvents(ctx context.Context, orchestratorService services.Orchestrator, logService *services.LogService, jobID uuid.UUID, lines int, send func(jobEvent) bool) error {
+	// endedExecutionID is the last execution whose terminal line arrived;
+	// CurrentJobExecutionID keeps returning it until an agent claims the retry.
+	var endedExecutionID uuid.UUID
+	for {
+		job, err := orchestratorService.GetJob(jobID)
+		if err != nil {
+			return err
+		}
+		if !send(jobEvent{Type: "status", Status: job.Status, Progress: job.Progress}) {
+			return nil
+		}
+		if isTerminalStatus(job.Status) {
+			return nil
+		}
+
+		executionID, err := orchestratorService.CurrentJobExecutionID(jobID)
+		if err != nil {
+			return err
+		}
+		if executionID == endedExecutionID {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(time.Second):
+			}
+			continue
+		}
+
+		backlog, err := logService.TailLogs(executionID, lines)
+		if err != nil {
+			return err
+		}
+		for _, line := range backlog {
+			if !send(jobEvent{Type: "log", Message: line.Message}) {
+				return nil
+			}
+		}
+
+		sub := logService.Subscribe(ctx, executionID)
+		terminal := false
+	followExecution:
+		for {
+			select {
+			case <-ctx.Done():
+				sub.Close()
+				return nil
+			case msg, ok := <-sub.Channel():
+				if !ok {
+					break followExecution
+				}
+				var parsed services.LogMessage
+				if err := json.Unmarshal([]byte(msg.Payload), &parsed); err != nil {
+					continue
+				}
+				if !send(jobEvent{Type: "log", Message: parsed.Line.Message}) {
+					sub.Close()
+					return nil
+				}
+				if parsed.Terminal {
+					terminal = true
+					break followExecution
+				}
+			}
+		}
+		sub.Close()
+		if !terminal {
+			// Subscription channel closed without a terminal line (e.g. Redis
+			// connection reset); re-check the job rather than spin.
+			continue
+		}
+		endedExecutionID = executionID
+		// A terminal log line means either this job is genuinely done, or it
+		// failed and was re-enqueued for retry: loop back to re-read the job's
+		// status and, if it's pending again, follow its new execution once an
+		// agent has claimed it.
+	}
+}
+
+// GetJobEvents streams a job's status, progress, and log lines as
+// structured Server-Sent Events: GET /jobs/{id}/events?lines=N.
+func GetJobEvents(orchestratorService services.Orchestrator, logService *services.LogService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid job ID"})
+			return
+		}
+		if _, err := orchestratorService.GetJob(id); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+			return
+		}
+
+		c.Writer.Header().Set("Content-Type", "text/event-stream")
+		c.Writer.Header().Set("Cache-Control", "no-cache")
+		c.Writer.Header().Set("Connection", "keep-alive")
+		c.Writer.WriteHeader(http.StatusOK)
+		flusher, canFlush := c.Writer.(http.Flusher)
+
+		streamJobEvents(c.Request.Context(), orchestratorService, logService, id, parseLogLines(c), func(ev jobEvent) bool {
+			payload, marshalErr := json.Marshal(ev)
+			if marshalErr != nil {
+				return true
+			}
+			if _, writeErr := fmt.Fprintf(c.Writer, "data: %s\n\n", payload); writeErr != nil {
+				return false
+			}
+			if canFlush {
+				flusher.Flush()
+			}
+			return true
+		})
+	}
+}
+
+// GetJobWS streams the same status/progress/log events as GetJobEvents over
+// a WebSocket connection instead of SSE: GET /jobs/{id}/ws?lines=N.
+func GetJobWS(orchestratorService services.Orchestrator, logService *services.LogService) gin.HandlerFunc {
+	upgrader := websocket.Upgrader{
+		// Job status streaming carries no cross-origin secrets and is
+		// already gated by middleware.Auth/RequireScope, so any origin may
+		// open the socket.
+		CheckOrigin: func(r *http.Request) bool { return true },
+	}
+
+	return func(c *gin.Context) {
+		id, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid job ID"})
+			return
+		}
+		if _, err := orchestratorService.GetJob(id); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+			return
+		}
+
+		conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		streamJobEvents(c.Request.Context(), orchestratorService, logService, id, parseLogLines(c), func(ev jobEvent) bool {
+			return conn.WriteJSON(ev) == nil
+		})
+	}
+}
+
+// GetJobLogs streams the live log for a job's most recent execution
+// attempt: GET /jobs/{id}/logs?follow=true&lines=N.
+func GetJobLogs(orchestratorService services.Orchestrator, logService *services.LogService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid job ID"})
+			return
+		}
+
+		job, err := orchestratorService.GetJob(id)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+			return
+		}
+
+		executionID, err := orchestratorService.CurrentJobExecutionID(id)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Job has no executions yet"})
+			return
+		}
+
+		streamExecutionLogs(c, logService, executionID, c.Query("follow") == "true", isTerminalStatus(job.Status))
+	}
+}
+
+// GetTaskExecutionLogs streams the live log for one task attempt:
+// GET /orchestration/tasks/{id}/executions/{execId}/logs?follow=true&lines=N.
+func GetTaskExecutionLogs(orchestratorService services.Orchestrator, logService *services.LogService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, err := uuid.Parse(c.Param("id")); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid task ID"})
+			return
+		}
+		execID, err := uuid.Parse(c.Param("execId"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid execution ID"})
+			return
+		}
+
+		execution, err := orchestratorService.GetTaskExecution(execID)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Execution not found"})
+			return
+		}
+
+		streamExecutionLogs(c, logService, execID, c.Query("follow") == "true", isTerminalStatus(execution.Status))
+	}
+}
+
+// GetWorkflowExecutionLogs streams the live log for one workflow attempt:
+// GET /workflows/{id}/executions/{execId}/logs?follow=true&lines=N.
+func GetWorkflowExecutionLogs(workflowService services.Workflow, logService *services.LogService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, err := uuid.Parse(c.Param("id")); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid workflow ID"})
+			return
+		}
+		execID, err := uuid.Parse(c.Param("execId"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid execution ID"})
+			return
+		}
+
+		execution, err := workflowService.GetWorkflowExecution(execID)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Execution not found"})
+			return
+		}
+
+		streamExecutionLogs(c, logService, execID, c.Query("follow") == "true", isTerminalStatus(execution.Status))
+	}
+}
@@ -0,0 +1,41 @@
+// Package metrics holds the domain-level Prometheus metrics that don't fit
+// middleware's generic HTTP RED metrics: workflow/job/queue health signals
+// the services package emits directly as it processes executions.
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// WorkflowExecutionsTotal counts every finished workflow execution by its
+// terminal status (completed/failed), recorded when WorkflowService.
+// ExecuteWorkflow returns.
+var WorkflowExecutionsTotal = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "workflow_executions_total",
+		Help: "Total number of finished workflow executions by status",
+	},
+	[]string{"status"},
+)
+
+// JobDurationSeconds records how long a job execution ran from claim to
+// terminal outcome, observed when AgentServer.CompleteJob/FailJob closes it
+// out.
+var JobDurationSeconds = promauto.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Name:    "job_duration_seconds",
+		Help:    "Duration of finished job executions in seconds",
+		Buckets: prometheus.DefBuckets,
+	},
+	[]string{"status"},
+)
+
+// TaskQueueDepth is the number of jobs currently pending and claimable,
+// sampled on every AgentServer.RunReaper tick.
+var TaskQueueDepth = promauto.NewGauge(
+	prometheus.GaugeOpts{
+		Name: "task_queue_depth",
+		Help: "Number of jobs currently pending in the claimable queue",
+	},
+)
@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"fintech-ai-platform/go-service/models"
+)
+
+func newScopeTestContext(principal *models.Principal) (*gin.Context, *httptest.ResponseRecorder) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	if principal != nil {
+		c.Set(principalContextKey, *principal)
+	}
+	return c, w
+}
+
+func TestRequireScope_Mismatch(t *testing.T) {
+	c, w := newScopeTestContext(&models.Principal{Subject: "user-1", Scopes: []string{"workflow:read"}})
+
+	RequireScope(models.ScopeJobAdmin)(c)
+
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusForbidden)
+	}
+	if !c.IsAborted() {
+		t.Fatal("expected context to be aborted on scope mismatch")
+	}
+}
+
+func TestRequireScope_Match(t *testing.T) {
+	c, w := newScopeTestContext(&models.Principal{Subject: "user-1", Scopes: []string{models.ScopeJobAdmin}})
+
+	RequireScope(models.ScopeJobAdmin)(c)
+
+	if w.Code != http.StatusOK && c.IsAborted() {
+		t.Fatalf("expected request to proceed, got status %d aborted=%v", w.Code, c.IsAborted())
+	}
+}
+
+func TestRequireScope_NoPrincipal(t *testing.T) {
+	c, w := newScopeTestContext(nil)
+
+	RequireScope(models.ScopeJobAdmin)(c)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+	if !c.IsAborted() {
+		t.Fatal("expected context to be aborted with no principal")
+	}
+}
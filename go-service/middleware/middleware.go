@@ -1,12 +1,15 @@
 package middleware
 
 import (
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 var (
@@ -26,12 +29,40 @@ var (
 		},
 		[]string{"method", "endpoint"},
 	)
+
+	httpRequestSizeBytes = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "http_request_size_bytes",
+			Help:    "Size of incoming HTTP request bodies",
+			Buckets: prometheus.ExponentialBuckets(64, 4, 8),
+		},
+		[]string{"method", "endpoint"},
+	)
+
+	httpResponseSizeBytes = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "http_response_size_bytes",
+			Help:    "Size of outgoing HTTP response bodies",
+			Buckets: prometheus.ExponentialBuckets(64, 4, 8),
+		},
+		[]string{"method", "endpoint"},
+	)
+
+	httpRequestsInFlight = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "http_requests_in_flight",
+			Help: "Number of HTTP requests currently being served",
+		},
+	)
 )
 
-// Logger middleware for request logging
+// Logger middleware for request logging. It must run after
+// otelgin.Middleware so param.Request.Context() carries the span otelgin
+// started; trace_id/span_id let an operator jump from a log line straight
+// to the matching trace in the tracing UI.
 func Logger(logger *logrus.Logger) gin.HandlerFunc {
 	return gin.LoggerWithFormatter(func(param gin.LogFormatterParams) string {
-		logger.WithFields(logrus.Fields{
+		fields := logrus.Fields{
 			"timestamp": param.TimeStamp.Format(time.RFC3339),
 			"status":    param.StatusCode,
 			"latency":   param.Latency,
@@ -39,7 +70,14 @@ func Logger(logger *logrus.Logger) gin.HandlerFunc {
 			"method":    param.Method,
 			"path":      param.Path,
 			"error":     param.ErrorMessage,
-		}).Info("HTTP Request")
+		}
+
+		if sc := trace.SpanContextFromContext(param.Request.Context()); sc.IsValid() {
+			fields["trace_id"] = sc.TraceID().String()
+			fields["span_id"] = sc.SpanID().String()
+		}
+
+		logger.WithFields(fields).Info("HTTP Request")
 
 		return ""
 	})
@@ -62,72 +100,49 @@ func CORS() gin.HandlerFunc {
 	})
 }
 
-// Metrics middleware for Prometheus metrics
+// Metrics middleware records the RED (rate/errors/duration) and USE
+// (utilization/saturation) metrics for every routed HTTP request. Requests
+// that don't match a registered route (c.FullPath() == "") are skipped so a
+// client probing random paths can't blow up label cardinality.
 func Metrics() gin.HandlerFunc {
 	return gin.HandlerFunc(func(c *gin.Context) {
 		start := time.Now()
 
+		httpRequestsInFlight.Inc()
 		c.Next()
+		httpRequestsInFlight.Dec()
 
-		duration := time.Since(start).Seconds()
-
-		httpRequestsTotal.WithLabelValues(
-			c.Request.Method,
-			c.FullPath(),
-			string(rune(c.Writer.Status())),
-		).Inc()
-
-		httpRequestDuration.WithLabelValues(
-			c.Request.Method,
-			c.FullPath(),
-		).Observe(duration)
-	})
-}
-
-// Auth middleware for API key authentication
-func Auth() gin.HandlerFunc {
-	return gin.HandlerFunc(func(c *gin.Context) {
-		apiKey := c.GetHeader("X-API-Key")
-		if apiKey == "" {
-			c.JSON(401, gin.H{"error": "API key required"})
-			c.Abort()
+		endpoint := c.FullPath()
+		if endpoint == "" {
 			return
 		}
 
-		// In production, validate against database or external service
-		if apiKey != "go-service-key" {
-			c.JSON(401, gin.H{"error": "Invalid API key"})
-			c.Abort()
-			return
-		}
+		duration := time.Since(start).Seconds()
+		status := strconv.Itoa(c.Writer.Status())
 
-		c.Next()
+		httpRequestsTotal.WithLabelValues(c.Request.Method, endpoint, status).Inc()
+		httpRequestDuration.WithLabelValues(c.Request.Method, endpoint).Observe(duration)
+		if c.Request.ContentLength > 0 {
+			httpRequestSizeBytes.WithLabelValues(c.Request.Method, endpoint).Observe(float64(c.Request.ContentLength))
+		}
+		httpResponseSizeBytes.WithLabelValues(c.Request.Method, endpoint).Observe(float64(c.Writer.Size()))
 	})
 }
 
-// RateLimit middleware for request rate limiting
-func RateLimit(limit int) gin.HandlerFunc {
-	// Simple in-memory rate limiter
-	// In production, use Redis or similar
-	clients := make(map[string]int)
-
+// TraceAttributes adds the workflow/task/job UUID a route operates on to
+// the span otelgin.Middleware already started, so a trace can be found by
+// the same ID callers use in API requests and responses. It must run
+// after otelgin.Middleware so trace.SpanFromContext resolves to that span
+// rather than a no-op one.
+func TraceAttributes() gin.HandlerFunc {
 	return gin.HandlerFunc(func(c *gin.Context) {
-		clientIP := c.ClientIP()
-
-		if clients[clientIP] >= limit {
-			c.JSON(429, gin.H{"error": "Rate limit exceeded"})
-			c.Abort()
-			return
+		span := trace.SpanFromContext(c.Request.Context())
+		if id := c.Param("id"); id != "" {
+			span.SetAttributes(attribute.String("go_service.id", id))
+		}
+		if execID := c.Param("execId"); execID != "" {
+			span.SetAttributes(attribute.String("go_service.execution_id", execID))
 		}
-
-		clients[clientIP]++
-
-		// Reset counter after 1 minute
-		go func() {
-			time.Sleep(time.Minute)
-			clients[clientIP] = 0
-		}()
-
 		c.Next()
 	})
 }
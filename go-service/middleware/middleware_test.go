@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestMetrics_StatusLabel(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Metrics())
+	router.GET("/things/:id", func(c *gin.Context) {
+		c.Status(http.StatusNotFound)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/things/abc", nil)
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	got := testutil.ToFloat64(httpRequestsTotal.WithLabelValues(http.MethodGet, "/things/:id", "404"))
+	if got != 1 {
+		t.Fatalf("http_requests_total{status=\"404\"} = %v, want 1", got)
+	}
+}
+
+// TestTraceAttributes_TagsIDParams verifies the active span (already
+// started by otelgin.Middleware, which must run first) is tagged with the
+// route's id/execId path params, so a trace can be found by the same ID a
+// caller used in the request.
+func TestTraceAttributes_TagsIDParams(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	recorder := tracetest.NewSpanRecorder()
+	tp := trace.NewTracerProvider(trace.WithSpanProcessor(recorder))
+	tracer := tp.Tracer("test")
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		ctx, span := tracer.Start(c.Request.Context(), c.FullPath())
+		defer span.End()
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	})
+	router.Use(TraceAttributes())
+	router.GET("/workflows/:id/executions/:execId/logs", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/workflows/wf-1/executions/exec-1/logs", nil)
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("got %d ended spans, want 1", len(spans))
+	}
+
+	attrs := make(map[string]string, 2)
+	for _, kv := range spans[0].Attributes() {
+		attrs[string(kv.Key)] = kv.Value.AsString()
+	}
+	if attrs["go_service.id"] != "wf-1" {
+		t.Errorf("go_service.id = %q, want %q", attrs["go_service.id"], "wf-1")
+	}
+	if attrs["go_service.execution_id"] != "exec-1" {
+		t.Errorf("go_service.execution_id = %q, want %q", attrs["go_service.execution_id"], "exec-1")
+	}
+}
+
+func TestMetrics_SkipsUnmatchedRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Metrics())
+
+	before := testutil.CollectAndCount(httpRequestsTotal)
+
+	req := httptest.NewRequest(http.MethodGet, "/no-such-route", nil)
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	after := testutil.CollectAndCount(httpRequestsTotal)
+	if after != before {
+		t.Fatalf("unmatched route changed http_requests_total series count: before=%d after=%d", before, after)
+	}
+}
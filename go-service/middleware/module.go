@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"fmt"
+
+	"github.com/go-redis/redis/v8"
+	"go.uber.org/fx"
+	"gorm.io/gorm"
+
+	"fintech-ai-platform/go-service/config"
+)
+
+// Module provides the Redis client and RateLimiter the HTTP middleware
+// chain needs.
+var Module = fx.Module("middleware",
+	fx.Provide(
+		newRedisClient,
+		newRateLimiter,
+	),
+)
+
+func newRedisClient(cfg *config.Config) *redis.Client {
+	return redis.NewClient(&redis.Options{
+		Addr:     fmt.Sprintf("%s:%s", cfg.Redis.Host, cfg.Redis.Port),
+		Password: cfg.Redis.Password,
+		DB:       cfg.Redis.DB,
+	})
+}
+
+// newRateLimiter wires a RateLimiter with db so per-key overrides (see
+// models.APIKeyLimit) apply; a key with no override row still falls back
+// to cfg.RateLimit.
+func newRateLimiter(redisClient *redis.Client, db *gorm.DB, cfg *config.Config) *RateLimiter {
+	return NewRateLimiter(redisClient, db, cfg.RateLimit)
+}
@@ -0,0 +1,177 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v8"
+	"gorm.io/gorm"
+
+	"fintech-ai-platform/go-service/config"
+	"fintech-ai-platform/go-service/models"
+)
+
+// tokenBucketScript is an atomic refill-and-decrement: it reads the
+// bucket's current tokens and last refill timestamp from a Redis hash,
+// refills based on elapsed time (capped at burst), decrements by one if a
+// token is available, and writes the result back with an expiry so idle
+// buckets don't linger forever. Running this as a single Lua script is what
+// lets replicas share one bucket without a read-then-write race. All
+// returned fields are pre-floored/ceiled in Lua so the Go side never has to
+// reconstruct a float from a truncated RESP integer.
+const tokenBucketScript = `
+local key = KEYS[1]
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local ttl = tonumber(ARGV[4])
+
+local bucket = redis.call('HMGET', key, 'tokens', 'last_refill')
+local tokens = tonumber(bucket[1])
+local lastRefill = tonumber(bucket[2])
+if tokens == nil then
+	tokens = burst
+	lastRefill = now
+end
+
+local elapsed = math.max(0, now - lastRefill)
+tokens = math.min(burst, tokens + elapsed * rate)
+
+local allowed = 0
+if tokens >= 1 then
+	allowed = 1
+	tokens = tokens - 1
+end
+
+redis.call('HMSET', key, 'tokens', tokens, 'last_refill', now)
+redis.call('EXPIRE', key, ttl)
+
+local retryAfter = 0
+if allowed == 0 then
+	retryAfter = math.ceil((1 - tokens) / rate)
+end
+local resetSeconds = math.ceil((burst - tokens) / rate)
+
+return {allowed, math.floor(tokens), retryAfter, resetSeconds}
+`
+
+// RateLimiter enforces a token-bucket quota per API key (or client IP, for
+// unauthenticated callers) using the Lua script above so the bucket is
+// shared correctly across replicas instead of living in each process's own
+// memory. An operator can override the default rate for a given key via
+// PUT /api/v1/auth/keys/{id}/limit (see AuthService.SetAPIKeyLimit); a
+// missing models.APIKeyLimit row falls back to cfg.
+type RateLimiter struct {
+	redis  *redis.Client
+	db     *gorm.DB
+	cfg    config.RateLimitConfig
+	script *redis.Script
+}
+
+// NewRateLimiter creates a RateLimiter backed by redisClient, with db used
+// to look up per-key overrides (may be nil, in which case cfg always
+// applies) and cfg as the default bucket for callers with no override.
+func NewRateLimiter(redisClient *redis.Client, db *gorm.DB, cfg config.RateLimitConfig) *RateLimiter {
+	return &RateLimiter{
+		redis:  redisClient,
+		db:     db,
+		cfg:    cfg,
+		script: redis.NewScript(tokenBucketScript),
+	}
+}
+
+// configFor returns the per-key override for keyPrefix (an APIKey.Prefix,
+// or the literal "ip:<address>" bucket id for an unauthenticated caller),
+// or r.cfg if none exists.
+func (r *RateLimiter) configFor(keyPrefix string) config.RateLimitConfig {
+	if r.db == nil {
+		return r.cfg
+	}
+	var override models.APIKeyLimit
+	if err := r.db.Where("key_prefix = ?", keyPrefix).First(&override).Error; err != nil {
+		return r.cfg
+	}
+	return config.RateLimitConfig{
+		RequestsPerSecond: override.RequestsPerSecond,
+		Burst:             override.Burst,
+	}
+}
+
+type bucketResult struct {
+	allowed           bool
+	remaining         int
+	retryAfterSeconds int
+	resetSeconds      int
+}
+
+// allow runs the token-bucket script for key under cfg. now is passed in
+// rather than read with time.Now() inside the script (Redis servers don't
+// all agree on wall-clock time, and Lua has no clock of its own anyway).
+func (r *RateLimiter) allow(ctx context.Context, key string, cfg config.RateLimitConfig, now time.Time) (bucketResult, error) {
+	res, err := r.script.Run(ctx, r.redis, []string{"ratelimit:" + key},
+		cfg.RequestsPerSecond, cfg.Burst, now.Unix(), rateLimitKeyTTLSeconds).Result()
+	if err != nil {
+		return bucketResult{}, err
+	}
+
+	vals, ok := res.([]interface{})
+	if !ok || len(vals) != 4 {
+		return bucketResult{}, errors.New("unexpected token bucket script result")
+	}
+	toInt := func(v interface{}) int {
+		n, _ := v.(int64)
+		return int(n)
+	}
+	return bucketResult{
+		allowed:           toInt(vals[0]) == 1,
+		remaining:         toInt(vals[1]),
+		retryAfterSeconds: toInt(vals[2]),
+		resetSeconds:      toInt(vals[3]),
+	}, nil
+}
+
+// rateLimitKeyTTLSeconds bounds how long an idle bucket's Redis hash lives;
+// it's set well above any realistic refill window so a burst of traffic
+// followed by silence doesn't reset the bucket early.
+const rateLimitKeyTTLSeconds = 3600
+
+// Middleware enforces the token bucket for the caller's API key, falling
+// back to their client IP when no key is presented. A Redis error fails
+// open (the request is let through) rather than turning a cache outage
+// into a full outage.
+func (r *RateLimiter) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader("X-API-Key")
+		keyPrefix := key
+		if key == "" {
+			key = "ip:" + c.ClientIP()
+			keyPrefix = key
+		} else if len(key) >= models.APIKeyPrefixLen {
+			keyPrefix = key[:models.APIKeyPrefixLen]
+		}
+		cfg := r.configFor(keyPrefix)
+
+		result, err := r.allow(c.Request.Context(), key, cfg, time.Now())
+		if err != nil {
+			c.Next()
+			return
+		}
+
+		c.Header("X-RateLimit-Limit", strconv.Itoa(cfg.Burst))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(result.remaining))
+		c.Header("X-RateLimit-Reset", strconv.Itoa(result.resetSeconds))
+
+		if !result.allowed {
+			c.Header("Retry-After", strconv.Itoa(result.retryAfterSeconds))
+			c.JSON(429, gin.H{"error": fmt.Sprintf("rate limit exceeded, retry after %ds", result.retryAfterSeconds)})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
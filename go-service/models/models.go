@@ -1,6 +1,8 @@
 package models
 
 import (
+	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -14,9 +16,16 @@ type Workflow struct {
 	Description string         `json:"description"`
 	Steps       []WorkflowStep `json:"steps" gorm:"foreignKey:WorkflowID"`
 	Status      string         `json:"status" gorm:"default:'draft'"`
-	CreatedAt   time.Time      `json:"created_at"`
-	UpdatedAt   time.Time      `json:"updated_at"`
-	DeletedAt   gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index"`
+	// InputSchema is a JSON Schema object compiled from CreateWorkflowRequest's
+	// Inputs by inputSchemaFromRequests. POST /workflows/{id}/dispatch
+	// validates its request body against it before executing, so manual runs
+	// can't reach the executor with missing or malformed parameters; see
+	// validateDispatchInput.
+	InputSchema string `json:"input_schema" gorm:"type:jsonb"`
+	ExecutionCounters
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index"`
 }
 
 // WorkflowStep represents a step in a workflow
@@ -25,53 +34,124 @@ type WorkflowStep struct {
 	WorkflowID  uuid.UUID `json:"workflow_id" gorm:"type:uuid;not null"`
 	Name        string    `json:"name" gorm:"not null"`
 	Description string    `json:"description"`
-	Order       int       `json:"order" gorm:"not null"`
+	Order       int       `json:"order" gorm:"not null"`        // display/tie-break order only; execution order comes from DependsOn
 	ServiceType string    `json:"service_type" gorm:"not null"` // ml, ocr, api, etc.
-	Config      string    `json:"config" gorm:"type:jsonb"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	// Config is service-specific JSON (e.g. {"url", "method"} for an api
+	// step). It may also set timeout_ms, max_retries, and
+	// retry_backoff_ms to override pkg/runner's defaults for this step's
+	// HTTP call; see runner.parseExecutionOptions.
+	Config string `json:"config" gorm:"type:jsonb"`
+	// DependsOn is a JSON array of step names that must complete before this
+	// step starts. Empty/null means the step has no predecessors and is
+	// eligible to run as soon as the workflow starts.
+	DependsOn string    `json:"depends_on" gorm:"type:jsonb"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// DependsOnNames parses the DependsOn JSON array into step names.
+func (s *WorkflowStep) DependsOnNames() ([]string, error) {
+	if s.DependsOn == "" {
+		return nil, nil
+	}
+
+	var names []string
+	if err := json.Unmarshal([]byte(s.DependsOn), &names); err != nil {
+		return nil, fmt.Errorf("step %s: invalid depends_on: %w", s.Name, err)
+	}
+	return names, nil
 }
 
 // Task represents an executable task
 type Task struct {
-	ID          uuid.UUID      `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
-	Name        string         `json:"name" gorm:"not null"`
-	Description string         `json:"description"`
-	Type        string         `json:"type" gorm:"not null"` // workflow, ml_prediction, ocr_extraction, etc.
-	Status      string         `json:"status" gorm:"default:'pending'"`
-	Config      string         `json:"config" gorm:"type:jsonb"`
-	Result      string         `json:"result" gorm:"type:jsonb"`
-	Error       string         `json:"error"`
-	StartedAt   *time.Time     `json:"started_at"`
-	CompletedAt *time.Time     `json:"completed_at"`
-	CreatedAt   time.Time      `json:"created_at"`
-	UpdatedAt   time.Time      `json:"updated_at"`
-	DeletedAt   gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index"`
+	ID          uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	Name        string    `json:"name" gorm:"not null"`
+	Description string    `json:"description"`
+	Type        string    `json:"type" gorm:"not null"` // workflow, ml_prediction, ocr_extraction, etc.
+	Status      string    `json:"status" gorm:"default:'pending'"`
+	// Config is type-specific JSON; for ml_prediction/ocr_extraction/
+	// api_call tasks it may also set timeout_ms, max_retries, and
+	// retry_backoff_ms, same as WorkflowStep.Config.
+	Config      string     `json:"config" gorm:"type:jsonb"`
+	Result      string     `json:"result" gorm:"type:jsonb"`
+	Error       string     `json:"error"`
+	StartedAt   *time.Time `json:"started_at"`
+	CompletedAt *time.Time `json:"completed_at"`
+	ExecutionCounters
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index"`
+}
+
+// ExecutionCounters is embedded in every row that owns an execution history
+// table (TaskExecution, JobExecution, WorkflowExecution). Each counter is
+// updated in the same transaction as the execution record that changes it,
+// so they're always a consistent rollup of the child rows rather than a
+// value that needs recomputing by scanning history.
+type ExecutionCounters struct {
+	TotalExecutions      int `json:"total_executions" gorm:"default:0"`
+	SucceededExecutions  int `json:"succeeded_executions" gorm:"default:0"`
+	FailedExecutions     int `json:"failed_executions" gorm:"default:0"`
+	InProgressExecutions int `json:"in_progress_executions" gorm:"default:0"`
+	StoppedExecutions    int `json:"stopped_executions" gorm:"default:0"`
 }
 
 // Job represents a long-running job
 type Job struct {
-	ID          uuid.UUID      `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
-	Name        string         `json:"name" gorm:"not null"`
-	Description string         `json:"description"`
-	Type        string         `json:"type" gorm:"not null"`
-	Status      string         `json:"status" gorm:"default:'pending'"`
-	Config      string         `json:"config" gorm:"type:jsonb"`
-	Progress    int            `json:"progress" gorm:"default:0"`
-	Result      string         `json:"result" gorm:"type:jsonb"`
-	Error       string         `json:"error"`
-	StartedAt   *time.Time     `json:"started_at"`
-	CompletedAt *time.Time     `json:"completed_at"`
-	CreatedAt   time.Time      `json:"created_at"`
-	UpdatedAt   time.Time      `json:"updated_at"`
-	DeletedAt   gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index"`
-}
-
-// TaskExecution represents a task execution
+	ID          uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	Name        string    `json:"name" gorm:"not null"`
+	Description string    `json:"description"`
+	Type        string    `json:"type" gorm:"not null"`
+	Status      string    `json:"status" gorm:"default:'pending'"`
+	Config      string    `json:"config" gorm:"type:jsonb"`
+	Progress    int       `json:"progress" gorm:"default:0"`
+	Result      string    `json:"result" gorm:"type:jsonb"`
+	Error       string    `json:"error"`
+	// AcquiredBy is the agent id that currently holds this job's lease, set
+	// by AgentServer.AcquireJob and cleared on completion/reap. Empty when
+	// the job is StatusPending and up for grabs.
+	AcquiredBy string `json:"acquired_by"`
+	// LeaseExpiresAt is renewed by Heartbeat RPCs from the holding agent.
+	// The server's reaper re-enqueues any StatusRunning job whose lease
+	// lapses without one, so a crashed agent can't strand work.
+	LeaseExpiresAt *time.Time `json:"lease_expires_at"`
+	// NextRetryAt holds a failed job out of the claimable pool until the
+	// backoff computed by AgentServer's retry policy elapses. Nil means the
+	// job is immediately claimable (its first attempt, or it never failed).
+	NextRetryAt *time.Time `json:"next_retry_at,omitempty"`
+	StartedAt   *time.Time `json:"started_at"`
+	CompletedAt *time.Time `json:"completed_at"`
+	ExecutionCounters
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index"`
+}
+
+// TaskExecution is one attempt at running a Task. ExecuteTask inserts a new
+// row per call instead of overwriting the Task's own Status/Result/Error, so
+// prior attempts stay queryable via GET /orchestration/tasks/{id}/executions.
 type TaskExecution struct {
 	ID        uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
 	TaskID    uuid.UUID  `json:"task_id" gorm:"type:uuid;not null"`
+	Attempt   int        `json:"attempt" gorm:"not null"`
+	Status    string     `json:"status" gorm:"not null"`
+	Input     string     `json:"input" gorm:"type:jsonb"`
+	Result    string     `json:"result" gorm:"type:jsonb"`
+	Error     string     `json:"error"`
+	StartedAt time.Time  `json:"started_at"`
+	EndedAt   *time.Time `json:"ended_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// JobExecution is one attempt at running a Job: one row per agent lease,
+// created when AgentServer.AcquireJob's claimNextJob hands the job to an
+// agent and finalized by CompleteJob/FailJob/StopJob.
+type JobExecution struct {
+	ID        uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	JobID     uuid.UUID  `json:"job_id" gorm:"type:uuid;not null"`
+	Attempt   int        `json:"attempt" gorm:"not null"`
 	Status    string     `json:"status" gorm:"not null"`
+	Input     string     `json:"input" gorm:"type:jsonb"`
 	Result    string     `json:"result" gorm:"type:jsonb"`
 	Error     string     `json:"error"`
 	StartedAt time.Time  `json:"started_at"`
@@ -79,20 +159,103 @@ type TaskExecution struct {
 	CreatedAt time.Time  `json:"created_at"`
 }
 
+// WorkflowExecution is one attempt at running a Workflow's DAG. Its
+// per-step detail lives in WorkflowStepExecution, fetched via
+// GET /workflows/{id}/executions/{execId}/steps.
+type WorkflowExecution struct {
+	ID         uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	WorkflowID uuid.UUID  `json:"workflow_id" gorm:"type:uuid;not null"`
+	Attempt    int        `json:"attempt" gorm:"not null"`
+	Status     string     `json:"status" gorm:"not null"`
+	Input      string     `json:"input" gorm:"type:jsonb"`
+	Result     string     `json:"result" gorm:"type:jsonb"`
+	Error      string     `json:"error"`
+	StartedAt  time.Time  `json:"started_at"`
+	EndedAt    *time.Time `json:"ended_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// WorkflowStepExecution is one step's result within a single
+// WorkflowExecution, keyed by step name rather than WorkflowStep ID since
+// ExecuteWorkflow resolves steps by name when building the DAG.
+type WorkflowStepExecution struct {
+	ID                  uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	WorkflowExecutionID uuid.UUID  `json:"workflow_execution_id" gorm:"type:uuid;not null"`
+	StepName            string     `json:"step_name" gorm:"not null"`
+	Status              string     `json:"status" gorm:"not null"`
+	Input               string     `json:"input" gorm:"type:jsonb"`
+	Result              string     `json:"result" gorm:"type:jsonb"`
+	Error               string     `json:"error"`
+	StartedAt           time.Time  `json:"started_at"`
+	EndedAt             *time.Time `json:"ended_at"`
+	CreatedAt           time.Time  `json:"created_at"`
+}
+
+// ExecutionLogLine is one line of a TaskExecution/JobExecution/
+// WorkflowExecution's live log, persisted as a ring buffer (see
+// services.LogService) so GET .../logs?follow=true can replay recent
+// history before switching to the Redis pub/sub feed for new lines.
+type ExecutionLogLine struct {
+	ID          uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	ExecutionID uuid.UUID `json:"execution_id" gorm:"type:uuid;not null;index"`
+	Seq         int       `json:"seq" gorm:"not null"`
+	Message     string    `json:"message"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// APIKeyLimit overrides the default rate-limit bucket for a specific API
+// key (or client IP, when no key is presented), letting operators set
+// premium/free tiers without redeploying. middleware.RateLimiter looks this
+// up on every request; a missing row means the config-file default applies.
+// KeyPrefix is an APIKey.Prefix (see AuthService.SetAPIKeyLimit), or the
+// literal "ip:<address>" bucket id for an unauthenticated caller — never the
+// plaintext key, which is not stored in Postgres.
+type APIKeyLimit struct {
+	ID                uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	KeyPrefix         string    `json:"key_prefix" gorm:"uniqueIndex;not null"`
+	RequestsPerSecond float64   `json:"requests_per_second" gorm:"not null"`
+	Burst             int       `json:"burst" gorm:"not null"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
 // Request/Response models
 
+// SetAPIKeyLimitRequest is the body of PUT /api/v1/auth/keys/{id}/limit.
+type SetAPIKeyLimitRequest struct {
+	RequestsPerSecond float64 `json:"requests_per_second" binding:"required"`
+	Burst             int     `json:"burst" binding:"required"`
+}
+
 type CreateWorkflowRequest struct {
 	Name        string              `json:"name" binding:"required"`
 	Description string              `json:"description"`
 	Steps       []CreateStepRequest `json:"steps"`
+	// Inputs describes the parameters a manual POST /workflows/{id}/dispatch
+	// call must supply; the server compiles it into the Workflow's
+	// InputSchema.
+	Inputs []WorkflowInput `json:"inputs"`
 }
 
 type CreateStepRequest struct {
-	Name        string `json:"name" binding:"required"`
-	Description string `json:"description"`
-	Order       int    `json:"order" binding:"required"`
-	ServiceType string `json:"service_type" binding:"required"`
-	Config      string `json:"config"`
+	Name        string   `json:"name" binding:"required"`
+	Description string   `json:"description"`
+	Order       int      `json:"order" binding:"required"`
+	ServiceType string   `json:"service_type" binding:"required"`
+	Config      string   `json:"config"`
+	DependsOn   []string `json:"depends_on"`
+}
+
+// WorkflowInput describes one parameter a workflow accepts from a manual
+// dispatch call. Type is a JSON Schema primitive: string, number, integer,
+// boolean, array, or object.
+type WorkflowInput struct {
+	Name        string      `json:"name" binding:"required"`
+	Type        string      `json:"type" binding:"required"`
+	Required    bool        `json:"required"`
+	Default     interface{} `json:"default"`
+	Description string      `json:"description"`
+	Enum        []string    `json:"enum"`
 }
 
 type CreateTaskRequest struct {
@@ -124,6 +287,11 @@ const (
 	StatusCompleted = "completed"
 	StatusFailed    = "failed"
 	StatusCancelled = "cancelled"
+	// StatusDeadLetter marks a job that exhausted AgentServer's retry
+	// policy (see AgentConfig.MaxAttempts). It no longer appears in
+	// claimNextJob's query; an operator must call RequeueDeadLetterJob to
+	// give it another attempt.
+	StatusDeadLetter = "dead_letter"
 )
 
 // Task types
@@ -134,3 +302,92 @@ const (
 	TaskTypeAPICall       = "api_call"
 	TaskTypeDataTransform = "data_transform"
 )
+
+// RBAC scopes. A principal (see Principal) must carry the scope a route
+// requires via middleware.RequireScope, or the request is rejected with
+// 403 regardless of how it authenticated.
+const (
+	ScopeWorkflowRead    = "workflow:read"
+	ScopeWorkflowWrite   = "workflow:write"
+	ScopeWorkflowExecute = "workflow:execute"
+	ScopeJobRead         = "job:read"
+	ScopeJobWrite        = "job:write"
+	ScopeJobAdmin        = "job:admin"
+	ScopeAuthAdmin       = "auth:admin"
+)
+
+// Principal is the authenticated caller attached to the request context by
+// middleware.Auth as c.Set("principal", ...), identifying who a JWT or API
+// key resolved to and what they're allowed to do. RequireScope checks its
+// Scopes; workflows and jobs created in the request do not record it as an
+// owner.
+type Principal struct {
+	Subject    string   `json:"subject"`
+	Scopes     []string `json:"scopes"`
+	AuthMethod string   `json:"auth_method"` // "jwt" or "api_key"
+}
+
+// HasScope reports whether p was granted scope.
+func (p Principal) HasScope(scope string) bool {
+	for _, s := range p.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// APIKeyPrefixLen is how many characters of an API key's plaintext are
+// retained (unhashed, in APIKey.Prefix and APIKeyLimit.KeyPrefix) so a
+// lookup can use an indexed column instead of a bcrypt comparison against
+// every row. Shared between services.AuthService and middleware.RateLimiter,
+// which both derive a key's prefix from the raw X-API-Key header the same
+// way.
+const APIKeyPrefixLen = 8
+
+// APIKey is a hashed, prefix-indexed credential presented via the
+// X-API-Key header as an alternative to a JWT. Only Prefix and Hash are
+// ever persisted; the plaintext key is returned to the caller once, at
+// creation or rotation time, and cannot be recovered afterward.
+type APIKey struct {
+	ID uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	// Prefix is the first APIKeyPrefixLen characters of the plaintext key,
+	// indexed so a lookup doesn't have to bcrypt-compare against every row
+	// in the table.
+	Prefix string `json:"prefix" gorm:"uniqueIndex;not null"`
+	Hash   string `json:"-" gorm:"not null"`
+	// Owner becomes the Subject of the Principal this key authenticates as.
+	Owner string `json:"owner" gorm:"not null"`
+	// Scopes is a JSON array of scope strings, e.g. ["workflow:read","job:admin"].
+	Scopes    string     `json:"scopes" gorm:"type:jsonb"`
+	Revoked   bool       `json:"revoked" gorm:"default:false"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// ScopesList parses Scopes into a slice of scope strings.
+func (k *APIKey) ScopesList() ([]string, error) {
+	if k.Scopes == "" {
+		return nil, nil
+	}
+	var scopes []string
+	if err := json.Unmarshal([]byte(k.Scopes), &scopes); err != nil {
+		return nil, fmt.Errorf("api key %s: invalid scopes: %w", k.ID, err)
+	}
+	return scopes, nil
+}
+
+// CreateAPIKeyRequest is the body of POST /api/v1/auth/keys.
+type CreateAPIKeyRequest struct {
+	Owner  string   `json:"owner" binding:"required"`
+	Scopes []string `json:"scopes" binding:"required"`
+}
+
+// APIKeyResponse wraps an APIKey with its one-time plaintext value. Key is
+// only ever populated on the create/rotate response; GET/list endpoints
+// return the APIKey alone.
+type APIKeyResponse struct {
+	APIKey
+	Key string `json:"key,omitempty"`
+}
@@ -0,0 +1,270 @@
+// Package runner holds the actual ML/OCR/API call implementations behind a
+// task, job, or workflow step. It has no dependency on gorm or the HTTP
+// server so it can be linked into both the API server (for in-request task
+// execution) and the standalone cmd/agent binary (for queue-pulled jobs)
+// without dragging either one into the other.
+package runner
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"fintech-ai-platform/go-service/config"
+)
+
+// Runner executes a single step against the platform's ML/OCR/API sibling
+// services.
+type Runner struct {
+	config     *config.Config
+	httpClient *http.Client
+}
+
+// New creates a Runner that calls out to the services described by cfg.
+func New(cfg *config.Config) *Runner {
+	return &Runner{config: cfg, httpClient: &http.Client{}}
+}
+
+// ExecutionOptions bounds a single step's HTTP call: how long one attempt
+// may run before it's cancelled, how many times a transient failure is
+// retried, and the base delay between retries.
+type ExecutionOptions struct {
+	Timeout      time.Duration
+	MaxRetries   int
+	RetryBackoff time.Duration
+}
+
+const (
+	defaultTimeout      = 30 * time.Second
+	defaultRetryBackoff = 500 * time.Millisecond
+)
+
+// executionOptionsJSON is the subset of a step/task's raw Config JSON that
+// tunes resilience. Durations are expressed in milliseconds to match the
+// convention set by config.AgentConfig.
+type executionOptionsJSON struct {
+	TimeoutMS      int `json:"timeout_ms"`
+	MaxRetries     int `json:"max_retries"`
+	RetryBackoffMS int `json:"retry_backoff_ms"`
+}
+
+// parseExecutionOptions reads timeout_ms/max_retries/retry_backoff_ms out of
+// a step or task's Config, falling back to sane defaults for whichever keys
+// are absent. rawConfig need not be valid JSON at all (e.g. an empty or
+// non-object config) since decode failures just leave the defaults in place.
+func parseExecutionOptions(rawConfig string) ExecutionOptions {
+	opts := ExecutionOptions{Timeout: defaultTimeout, RetryBackoff: defaultRetryBackoff}
+
+	var parsed executionOptionsJSON
+	if rawConfig != "" {
+		_ = json.Unmarshal([]byte(rawConfig), &parsed)
+	}
+	if parsed.TimeoutMS > 0 {
+		opts.Timeout = time.Duration(parsed.TimeoutMS) * time.Millisecond
+	}
+	if parsed.MaxRetries > 0 {
+		opts.MaxRetries = parsed.MaxRetries
+	}
+	if parsed.RetryBackoffMS > 0 {
+		opts.RetryBackoff = time.Duration(parsed.RetryBackoffMS) * time.Millisecond
+	}
+	return opts
+}
+
+// ExecuteMLPrediction calls the ML service's document classification
+// endpoint.
+func (r *Runner) ExecuteMLPrediction(ctx context.Context, rawConfig string, input map[string]interface{}) (map[string]interface{}, error) {
+	url := fmt.Sprintf("%s/api/v1/predictions/predict", r.config.Services.MLService)
+
+	requestBody := map[string]interface{}{
+		"model_type": "document_classification",
+		"input_data": input,
+	}
+
+	body, err := json.Marshal(requestBody)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := r.doWithRetry(ctx, parseExecutionOptions(rawConfig), func(reqCtx context.Context) (*http.Request, error) {
+		req, err := http.NewRequestWithContext(reqCtx, http.MethodPost, url, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		return req, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var result map[string]interface{}
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ExecuteOCRExtraction calls the OCR service's extraction endpoint.
+func (r *Runner) ExecuteOCRExtraction(ctx context.Context, rawConfig string, input map[string]interface{}) (map[string]interface{}, error) {
+	url := fmt.Sprintf("%s/api/v1/ocr/extract", r.config.Services.OCRService)
+
+	body, err := json.Marshal(input)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := r.doWithRetry(ctx, parseExecutionOptions(rawConfig), func(reqCtx context.Context) (*http.Request, error) {
+		req, err := http.NewRequestWithContext(reqCtx, http.MethodPost, url, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		return req, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var result map[string]interface{}
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ExecuteAPICall makes an arbitrary HTTP call described by a task/step's raw
+// JSON config (expects at least "url" and "method" keys).
+func (r *Runner) ExecuteAPICall(ctx context.Context, rawConfig string, input map[string]interface{}) (map[string]interface{}, error) {
+	var cfg map[string]interface{}
+	if err := json.Unmarshal([]byte(rawConfig), &cfg); err != nil {
+		return nil, err
+	}
+
+	apiURL, _ := cfg["url"].(string)
+	method, _ := cfg["method"].(string)
+
+	var bodyBytes []byte
+	if len(input) > 0 {
+		var err error
+		bodyBytes, err = json.Marshal(input)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	respBody, err := r.doWithRetry(ctx, parseExecutionOptions(rawConfig), func(reqCtx context.Context) (*http.Request, error) {
+		var reqBody io.Reader
+		if bodyBytes != nil {
+			reqBody = bytes.NewReader(bodyBytes)
+		}
+		req, err := http.NewRequestWithContext(reqCtx, method, apiURL, reqBody)
+		if err != nil {
+			return nil, err
+		}
+		if bodyBytes != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		return req, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var result map[string]interface{}
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// doWithRetry runs newReq under a per-attempt timeout, retrying transient
+// failures (network errors, 429, 503) with exponential backoff that honors
+// a 429/503 response's Retry-After header, and giving up immediately on any
+// other 4xx. ctx bounds the whole call, including time spent sleeping
+// between retries, so StopTask cancelling it aborts retries too.
+func (r *Runner) doWithRetry(ctx context.Context, opts ExecutionOptions, newReq func(context.Context) (*http.Request, error)) ([]byte, error) {
+	var lastErr error
+
+	for attempt := 0; attempt <= opts.MaxRetries; attempt++ {
+		body, retryable, retryAfter, err := r.attempt(ctx, opts, newReq)
+		if err == nil {
+			return body, nil
+		}
+		lastErr = err
+		if !retryable || attempt == opts.MaxRetries {
+			break
+		}
+
+		wait := retryAfter
+		if wait <= 0 {
+			wait = opts.RetryBackoff * time.Duration(math.Pow(2, float64(attempt)))
+		}
+		if !sleepCtx(ctx, wait) {
+			return nil, ctx.Err()
+		}
+	}
+
+	return nil, lastErr
+}
+
+// attempt runs a single try of newReq, reporting whether the failure (if
+// any) is worth retrying and how long the server asked us to wait first.
+func (r *Runner) attempt(ctx context.Context, opts ExecutionOptions, newReq func(context.Context) (*http.Request, error)) (body []byte, retryable bool, retryAfter time.Duration, err error) {
+	attemptCtx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+
+	req, err := newReq(attemptCtx)
+	if err != nil {
+		return nil, false, 0, err
+	}
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return nil, true, 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		retryable := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable
+		return nil, retryable, parseRetryAfter(resp.Header.Get("Retry-After")), fmt.Errorf("request failed with status %d", resp.StatusCode)
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	return respBody, false, 0, err
+}
+
+// sleepCtx waits for d or returns false early if ctx is done first.
+func sleepCtx(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// parseRetryAfter supports both forms RFC 7231 allows: a number of seconds
+// or an HTTP date. It returns 0 if the header is absent or unparseable,
+// which tells the caller to fall back to its own backoff schedule.
+func parseRetryAfter(header string) time.Duration {
+	if header == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(header); err == nil {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(header); err == nil {
+		return time.Until(t)
+	}
+	return 0
+}
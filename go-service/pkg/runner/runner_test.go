@@ -0,0 +1,60 @@
+package runner
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestParseExecutionOptions_Defaults(t *testing.T) {
+	opts := parseExecutionOptions("")
+	if opts.Timeout != defaultTimeout {
+		t.Errorf("Timeout = %v, want default %v", opts.Timeout, defaultTimeout)
+	}
+	if opts.RetryBackoff != defaultRetryBackoff {
+		t.Errorf("RetryBackoff = %v, want default %v", opts.RetryBackoff, defaultRetryBackoff)
+	}
+	if opts.MaxRetries != 0 {
+		t.Errorf("MaxRetries = %d, want 0", opts.MaxRetries)
+	}
+}
+
+func TestParseExecutionOptions_Overrides(t *testing.T) {
+	opts := parseExecutionOptions(`{"timeout_ms": 5000, "max_retries": 3, "retry_backoff_ms": 250}`)
+	if opts.Timeout != 5*time.Second {
+		t.Errorf("Timeout = %v, want 5s", opts.Timeout)
+	}
+	if opts.MaxRetries != 3 {
+		t.Errorf("MaxRetries = %d, want 3", opts.MaxRetries)
+	}
+	if opts.RetryBackoff != 250*time.Millisecond {
+		t.Errorf("RetryBackoff = %v, want 250ms", opts.RetryBackoff)
+	}
+}
+
+func TestParseExecutionOptions_InvalidJSONFallsBackToDefaults(t *testing.T) {
+	opts := parseExecutionOptions(`not json`)
+	if opts.Timeout != defaultTimeout || opts.RetryBackoff != defaultRetryBackoff || opts.MaxRetries != 0 {
+		t.Errorf("parseExecutionOptions(invalid) = %+v, want defaults", opts)
+	}
+}
+
+func TestParseRetryAfter_Seconds(t *testing.T) {
+	if got := parseRetryAfter("5"); got != 5*time.Second {
+		t.Errorf("parseRetryAfter(\"5\") = %v, want 5s", got)
+	}
+}
+
+func TestParseRetryAfter_Empty(t *testing.T) {
+	if got := parseRetryAfter(""); got != 0 {
+		t.Errorf("parseRetryAfter(\"\") = %v, want 0", got)
+	}
+}
+
+func TestParseRetryAfter_HTTPDate(t *testing.T) {
+	future := time.Now().Add(10 * time.Second).UTC().Format(http.TimeFormat)
+	got := parseRetryAfter(future)
+	if got <= 0 || got > 11*time.Second {
+		t.Errorf("parseRetryAfter(%q) = %v, want ~10s", future, got)
+	}
+}
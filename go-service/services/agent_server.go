@@ -0,0 +1,495 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"fintech-ai-platform/go-service/metrics"
+	"fintech-ai-platform/go-service/models"
+	agentv1 "fintech-ai-platform/go-service/proto/agent/v1"
+)
+
+// AgentServer implements agentv1.AgentServiceServer. It hands pending jobs
+// out to long-polling cmd/agent workers instead of executing them in-process,
+// so job execution scales independently of the API server and survives its
+// restarts.
+type AgentServer struct {
+	agentv1.UnimplementedAgentServiceServer
+
+	db             *gorm.DB
+	logger         *logrus.Logger
+	pollInterval   time.Duration
+	leaseDuration  time.Duration
+	maxAttempts    int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	logs           *LogService
+}
+
+// NewAgentServer creates an AgentServer backed by db. pollInterval controls
+// how often a blocked AcquireJob request re-checks the queue; leaseDuration
+// is how long a claimed job is held before the reaper considers it
+// abandoned. maxAttempts caps how many times a job may be claimed before
+// FailJob/reapExpiredLeases move it to models.StatusDeadLetter instead of
+// re-enqueuing it; each retry in between waits an exponential backoff
+// bounded by [initialBackoff, maxBackoff]. logs publishes a line to the
+// job's execution log on every progress heartbeat and terminal transition,
+// backing GET /jobs/{id}/logs and /events.
+func NewAgentServer(pollInterval, leaseDuration time.Duration, maxAttempts int, initialBackoff, maxBackoff time.Duration, db *gorm.DB, logs *LogService) *AgentServer {
+	return &AgentServer{
+		db:             db,
+		logger:         logrus.New(),
+		pollInterval:   pollInterval,
+		leaseDuration:  leaseDuration,
+		maxAttempts:    maxAttempts,
+		initialBackoff: initialBackoff,
+		maxBackoff:     maxBackoff,
+		logs:           logs,
+	}
+}
+
+// backoffFor returns how long to hold a job out of the claimable pool before
+// its (attempt+1)th try, doubling from initialBackoff and capping at
+// maxBackoff.
+func (s *AgentServer) backoffFor(attempt int) time.Duration {
+	backoff := s.initialBackoff
+	for i := 1; i < attempt; i++ {
+		backoff *= 2
+		if backoff >= s.maxBackoff {
+			return s.maxBackoff
+		}
+	}
+	return backoff
+}
+
+// AcquireJob long-polls the job queue once per request the agent sends on
+// the stream, replying with a job as soon as one is available and blocking
+// (without holding a DB connection) otherwise.
+func (s *AgentServer) AcquireJob(stream agentv1.AgentService_AcquireJobServer) error {
+	ctx := stream.Context()
+
+	for {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		job, err := s.waitForJob(ctx, req.AgentId)
+		if err != nil {
+			return err
+		}
+		if job == nil {
+			// Context was cancelled (agent disconnected) while long-polling.
+			return ctx.Err()
+		}
+
+		if err := stream.Send(&agentv1.AcquireJobResponse{
+			JobId:   job.ID.String(),
+			JobName: job.Name,
+			JobType: job.Type,
+			Config:  job.Config,
+		}); err != nil {
+			return err
+		}
+	}
+}
+
+// waitForJob blocks, polling the queue every s.pollInterval, until a pending
+// job is claimed for agentID or ctx is done.
+func (s *AgentServer) waitForJob(ctx context.Context, agentID string) (*models.Job, error) {
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		job, err := s.claimNextJob(agentID)
+		if err != nil {
+			return nil, err
+		}
+		if job != nil {
+			return job, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// claimNextJob grabs the oldest pending job with SELECT ... FOR UPDATE SKIP
+// LOCKED so concurrent agents never race over the same row, marks it
+// running under the calling agent's lease, and opens the JobExecution row
+// for this attempt (closed out by CompleteJob/FailJob/StopJob).
+func (s *AgentServer) claimNextJob(agentID string) (*models.Job, error) {
+	var job models.Job
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND (next_retry_at IS NULL OR next_retry_at <= ?)", models.StatusPending, time.Now()).
+			Order("created_at").
+			First(&job).Error
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		lease := now.Add(s.leaseDuration)
+		job.Status = models.StatusRunning
+		job.AcquiredBy = agentID
+		job.LeaseExpiresAt = &lease
+		job.NextRetryAt = nil
+		job.StartedAt = &now
+		job.TotalExecutions++
+		job.InProgressExecutions++
+		if err := tx.Save(&job).Error; err != nil {
+			return err
+		}
+
+		// Number the attempt from the job's execution history rather than
+		// TotalExecutions, which RequeueDeadLetterJob resets, so attempt
+		// numbers keep increasing across requeues.
+		var priorAttempts int64
+		if err := tx.Model(&models.JobExecution{}).Where("job_id = ?", job.ID).Count(&priorAttempts).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(&models.JobExecution{
+			JobID:     job.ID,
+			Attempt:   int(priorAttempts) + 1,
+			Status:    models.StatusRunning,
+			Input:     job.Config,
+			StartedAt: now,
+		}).Error
+	})
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// currentExecution returns the open (not yet ended) JobExecution for a job,
+// i.e. the attempt the calling agent currently holds the lease for.
+func currentExecution(tx *gorm.DB, jobID uuid.UUID) (*models.JobExecution, error) {
+	var execution models.JobExecution
+	err := tx.Where("job_id = ? AND ended_at IS NULL", jobID).Order("attempt DESC").First(&execution).Error
+	if err != nil {
+		return nil, err
+	}
+	return &execution, nil
+}
+
+// UpdateJob records incremental progress for a job the agent currently
+// holds, and publishes it to the job's execution log so a
+// GET /jobs/{id}/logs?follow=true caller sees it without polling GET
+// /jobs/{id}.
+func (s *AgentServer) UpdateJob(ctx context.Context, req *agentv1.UpdateJobRequest) (*agentv1.UpdateJobResponse, error) {
+	id, err := uuid.Parse(req.JobId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid job id: %v", err)
+	}
+
+	var executionID uuid.UUID
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&models.Job{}).
+			Where("id = ? AND acquired_by = ?", id, req.AgentId).
+			Update("progress", req.Progress)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		execution, err := currentExecution(tx, id)
+		if err != nil {
+			return err
+		}
+		executionID = execution.ID
+		return nil
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, status.Errorf(codes.NotFound, "job %s not held by agent %s", req.JobId, req.AgentId)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "update job: %v", err)
+	}
+
+	if logErr := s.logs.AppendLog(ctx, executionID, fmt.Sprintf("progress: %d%%", req.Progress), false); logErr != nil {
+		s.logger.WithError(logErr).Warn("failed to publish job progress log line")
+	}
+
+	return &agentv1.UpdateJobResponse{}, nil
+}
+
+// CompleteJob records a successful terminal result, releases the job's
+// lease, and closes out the JobExecution opened for this attempt.
+func (s *AgentServer) CompleteJob(ctx context.Context, req *agentv1.CompleteJobRequest) (*agentv1.CompleteJobResponse, error) {
+	id, err := uuid.Parse(req.JobId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid job id: %v", err)
+	}
+
+	now := time.Now()
+	var executionID uuid.UUID
+	var startedAt time.Time
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		execution, err := currentExecution(tx, id)
+		if err != nil {
+			return err
+		}
+		executionID = execution.ID
+		startedAt = execution.StartedAt
+		execution.Status = models.StatusCompleted
+		execution.Result = req.Result
+		execution.EndedAt = &now
+		if err := tx.Save(execution).Error; err != nil {
+			return err
+		}
+
+		res := tx.Model(&models.Job{}).
+			Where("id = ? AND acquired_by = ?", id, req.AgentId).
+			Updates(map[string]interface{}{
+				"status":                 models.StatusCompleted,
+				"progress":               100,
+				"result":                 req.Result,
+				"completed_at":           now,
+				"acquired_by":            "",
+				"lease_expires_at":       nil,
+				"in_progress_executions": gorm.Expr("in_progress_executions - 1"),
+				"succeeded_executions":   gorm.Expr("succeeded_executions + 1"),
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, status.Errorf(codes.NotFound, "job %s not held by agent %s", req.JobId, req.AgentId)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "complete job: %v", err)
+	}
+
+	if logErr := s.logs.AppendLog(ctx, executionID, "job completed", true); logErr != nil {
+		s.logger.WithError(logErr).Warn("failed to publish job completion log line")
+	}
+	metrics.JobDurationSeconds.WithLabelValues(models.StatusCompleted).Observe(now.Sub(startedAt).Seconds())
+
+	return &agentv1.CompleteJobResponse{}, nil
+}
+
+// FailJob records a failed attempt, releases the job's lease, and closes out
+// the JobExecution opened for this attempt. The job goes back to
+// StatusPending after backoffFor's delay, or to StatusDeadLetter once it has
+// been claimed maxAttempts times.
+func (s *AgentServer) FailJob(ctx context.Context, req *agentv1.FailJobRequest) (*agentv1.FailJobResponse, error) {
+	id, err := uuid.Parse(req.JobId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid job id: %v", err)
+	}
+
+	now := time.Now()
+	var executionID uuid.UUID
+	var startedAt time.Time
+	var deadLettered bool
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		execution, err := currentExecution(tx, id)
+		if err != nil {
+			return err
+		}
+		executionID = execution.ID
+		startedAt = execution.StartedAt
+		execution.Status = models.StatusFailed
+		execution.Error = req.Error
+		execution.EndedAt = &now
+		if err := tx.Save(execution).Error; err != nil {
+			return err
+		}
+
+		var job models.Job
+		if err := tx.Where("id = ? AND acquired_by = ?", id, req.AgentId).First(&job).Error; err != nil {
+			return err
+		}
+
+		updates := map[string]interface{}{
+			"error":                  req.Error,
+			"acquired_by":            "",
+			"lease_expires_at":       nil,
+			"in_progress_executions": gorm.Expr("in_progress_executions - 1"),
+			"failed_executions":      gorm.Expr("failed_executions + 1"),
+		}
+		if job.TotalExecutions < s.maxAttempts {
+			nextRetry := now.Add(s.backoffFor(job.TotalExecutions))
+			updates["status"] = models.StatusPending
+			updates["next_retry_at"] = nextRetry
+		} else {
+			deadLettered = true
+			updates["status"] = models.StatusDeadLetter
+			updates["completed_at"] = now
+		}
+
+		res := tx.Model(&models.Job{}).Where("id = ? AND acquired_by = ?", id, req.AgentId).Updates(updates)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, status.Errorf(codes.NotFound, "job %s not held by agent %s", req.JobId, req.AgentId)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "fail job: %v", err)
+	}
+
+	logMsg := fmt.Sprintf("job failed, will retry: %s", req.Error)
+	if deadLettered {
+		logMsg = fmt.Sprintf("job failed permanently after %d attempts, moved to dead letter queue: %s", s.maxAttempts, req.Error)
+	}
+	if logErr := s.logs.AppendLog(ctx, executionID, logMsg, true); logErr != nil {
+		s.logger.WithError(logErr).Warn("failed to publish job failure log line")
+	}
+	metrics.JobDurationSeconds.WithLabelValues(models.StatusFailed).Observe(now.Sub(startedAt).Seconds())
+
+	return &agentv1.FailJobResponse{}, nil
+}
+
+// Heartbeat renews the lease on every job ID the agent reports holding.
+func (s *AgentServer) Heartbeat(ctx context.Context, req *agentv1.HeartbeatRequest) (*agentv1.HeartbeatResponse, error) {
+	if len(req.JobIds) > 0 {
+		lease := time.Now().Add(s.leaseDuration)
+		if err := s.db.Model(&models.Job{}).
+			Where("id IN ? AND acquired_by = ?", req.JobIds, req.AgentId).
+			Update("lease_expires_at", lease).Error; err != nil {
+			return nil, status.Errorf(codes.Internal, "heartbeat: %v", err)
+		}
+	}
+
+	return &agentv1.HeartbeatResponse{
+		NextHeartbeatSeconds: int64(s.leaseDuration.Seconds() / 2),
+	}, nil
+}
+
+// RunReaper re-enqueues StatusRunning jobs whose lease expired without a
+// heartbeat, so a crashed or partitioned agent can't strand work forever.
+// It blocks until ctx is done and should be started in its own goroutine.
+func (s *AgentServer) RunReaper(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.reapExpiredLeases(); err != nil {
+				s.logger.WithError(err).Error("failed to reap expired job leases")
+			}
+			s.sampleQueueDepth()
+		}
+	}
+}
+
+// sampleQueueDepth refreshes metrics.TaskQueueDepth with the current count
+// of claimable jobs. It runs on the same ticker as the reaper rather than
+// its own, since both only need reaper-frequency freshness.
+func (s *AgentServer) sampleQueueDepth() {
+	var depth int64
+	if err := s.db.Model(&models.Job{}).
+		Where("status = ? AND (next_retry_at IS NULL OR next_retry_at <= ?)", models.StatusPending, time.Now()).
+		Count(&depth).Error; err != nil {
+		s.logger.WithError(err).Warn("failed to sample task queue depth")
+		return
+	}
+	metrics.TaskQueueDepth.Set(float64(depth))
+}
+
+// reapExpiredLeases re-enqueues every StatusRunning job whose lease lapsed,
+// closing out each one's open JobExecution as a failed (timed-out) attempt
+// so it doesn't stay stuck "in progress" forever in the execution history.
+func (s *AgentServer) reapExpiredLeases() error {
+	var expired []models.Job
+	if err := s.db.Where("status = ? AND lease_expires_at < ?", models.StatusRunning, time.Now()).Find(&expired).Error; err != nil {
+		return err
+	}
+
+	const reapError = "lease expired: agent stopped sending heartbeats"
+
+	for _, job := range expired {
+		job := job
+		now := time.Now()
+		var executionID uuid.UUID
+		var deadLettered bool
+		if err := s.db.Transaction(func(tx *gorm.DB) error {
+			execution, err := currentExecution(tx, job.ID)
+			if err == nil {
+				executionID = execution.ID
+				execution.Status = models.StatusFailed
+				execution.Error = reapError
+				execution.EndedAt = &now
+				if err := tx.Save(execution).Error; err != nil {
+					return err
+				}
+			} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+				return err
+			}
+
+			updates := map[string]interface{}{
+				"error":                  reapError,
+				"acquired_by":            "",
+				"lease_expires_at":       nil,
+				"in_progress_executions": gorm.Expr("in_progress_executions - 1"),
+				"failed_executions":      gorm.Expr("failed_executions + 1"),
+			}
+			if job.TotalExecutions < s.maxAttempts {
+				nextRetry := now.Add(s.backoffFor(job.TotalExecutions))
+				updates["status"] = models.StatusPending
+				updates["next_retry_at"] = nextRetry
+			} else {
+				deadLettered = true
+				updates["status"] = models.StatusDeadLetter
+				updates["completed_at"] = now
+			}
+
+			return tx.Model(&models.Job{}).Where("id = ?", job.ID).Updates(updates).Error
+		}); err != nil {
+			return err
+		}
+
+		if executionID != uuid.Nil {
+			logMsg := reapError + ", will retry"
+			if deadLettered {
+				logMsg = fmt.Sprintf("%s, moved to dead letter queue after %d attempts", reapError, s.maxAttempts)
+			}
+			if logErr := s.logs.AppendLog(context.Background(), executionID, logMsg, true); logErr != nil {
+				s.logger.WithError(logErr).Warn("failed to publish job reap log line")
+			}
+		}
+	}
+
+	return nil
+}
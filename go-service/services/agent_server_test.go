@@ -0,0 +1,79 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sirupsen/logrus"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func newTestAgentServer(t *testing.T) (*AgentServer, sqlmock.Sqlmock) {
+	t.Helper()
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{
+		Conn:       sqlDB,
+		DriverName: "postgres",
+	}), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("gorm.Open: %v", err)
+	}
+
+	return &AgentServer{
+		db:            gormDB,
+		logger:        logrus.New(),
+		leaseDuration: time.Minute,
+		maxAttempts:   3,
+	}, mock
+}
+
+// TestBackoffFor verifies the exponential doubling from initialBackoff and
+// the cap at maxBackoff that FailJob/reapExpiredLeases rely on to compute
+// next_retry_at.
+func TestBackoffFor(t *testing.T) {
+	s := &AgentServer{initialBackoff: time.Second, maxBackoff: 10 * time.Second}
+
+	cases := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{attempt: 1, want: time.Second},
+		{attempt: 2, want: 2 * time.Second},
+		{attempt: 3, want: 4 * time.Second},
+		{attempt: 4, want: 8 * time.Second},
+		{attempt: 5, want: 10 * time.Second}, // would be 16s uncapped
+		{attempt: 10, want: 10 * time.Second},
+	}
+	for _, tc := range cases {
+		if got := s.backoffFor(tc.attempt); got != tc.want {
+			t.Errorf("backoffFor(%d) = %v, want %v", tc.attempt, got, tc.want)
+		}
+	}
+}
+
+// TestClaimNextJob_NoPendingJob verifies an empty queue is reported as "no
+// job" rather than an error, so waitForJob's long-poll keeps ticking
+// instead of the agent's stream erroring out.
+func TestClaimNextJob_NoPendingJob(t *testing.T) {
+	s, mock := newTestAgentServer(t)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT \* FROM "jobs" WHERE \(status = \$1 AND \(next_retry_at IS NULL OR next_retry_at <= \$2\)\) AND "jobs"."deleted_at" IS NULL ORDER BY created_at,"jobs"."id" LIMIT 1 FOR UPDATE SKIP LOCKED`).
+		WillReturnRows(sqlmock.NewRows(nil))
+	mock.ExpectRollback()
+
+	job, err := s.claimNextJob("agent-1")
+	if err != nil {
+		t.Fatalf("claimNextJob: %v", err)
+	}
+	if job != nil {
+		t.Errorf("claimNextJob() = %+v, want nil job on an empty queue", job)
+	}
+}
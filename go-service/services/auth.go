@@ -0,0 +1,221 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+
+	"fintech-ai-platform/go-service/config"
+	"fintech-ai-platform/go-service/models"
+)
+
+var (
+	// ErrInvalidCredentials covers both an unrecognized API key and a JWT
+	// that fails signature/expiry validation, deliberately not distinguishing
+	// the two in the response so callers can't use it to enumerate keys.
+	ErrInvalidCredentials = errors.New("invalid credentials")
+	ErrRevokedKey         = errors.New("api key has been revoked")
+)
+
+// jwtClaims is the claims shape middleware.Auth expects: Scopes drives
+// RequireScope checks, and the embedded RegisteredClaims gives us
+// exp/iss validation for free from jwt/v5.
+type jwtClaims struct {
+	Scopes []string `json:"scopes"`
+	jwt.RegisteredClaims
+}
+
+// AuthService validates JWTs and Postgres-backed API keys, and manages the
+// key store behind POST/DELETE /api/v1/auth/keys.
+type AuthService struct {
+	db     *gorm.DB
+	config *config.Config
+}
+
+// NewAuthService creates an AuthService backed by db.
+func NewAuthService(db *gorm.DB) *AuthService {
+	return &AuthService{db: db, config: config.LoadConfig()}
+}
+
+// ValidateJWT parses and validates tokenString against the configured
+// algorithm and signing key, returning the Principal it resolves to.
+func (s *AuthService) ValidateJWT(tokenString string) (*models.Principal, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &jwtClaims{}, func(t *jwt.Token) (interface{}, error) {
+		switch s.config.Auth.JWTAlgorithm {
+		case "RS256":
+			if _, ok := t.Method.(*jwt.SigningMethodRSA); !ok {
+				return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
+			}
+			return jwt.ParseRSAPublicKeyFromPEM([]byte(s.config.Auth.JWTPublicKey))
+		default:
+			if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+				return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
+			}
+			return []byte(s.config.Auth.JWTSecret), nil
+		}
+	}, jwt.WithIssuer(s.config.Auth.Issuer))
+	if err != nil || !token.Valid {
+		return nil, ErrInvalidCredentials
+	}
+
+	claims, ok := token.Claims.(*jwtClaims)
+	if !ok {
+		return nil, ErrInvalidCredentials
+	}
+
+	return &models.Principal{
+		Subject:    claims.Subject,
+		Scopes:     claims.Scopes,
+		AuthMethod: "jwt",
+	}, nil
+}
+
+// AuthenticateAPIKey looks up rawKey by its prefix and, on a bcrypt match
+// against a non-revoked row, returns the Principal it resolves to.
+func (s *AuthService) AuthenticateAPIKey(rawKey string) (*models.Principal, error) {
+	if len(rawKey) < models.APIKeyPrefixLen {
+		return nil, ErrInvalidCredentials
+	}
+
+	var key models.APIKey
+	if err := s.db.Where("prefix = ?", rawKey[:models.APIKeyPrefixLen]).First(&key).Error; err != nil {
+		return nil, ErrInvalidCredentials
+	}
+	if key.Revoked {
+		return nil, ErrRevokedKey
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(key.Hash), []byte(rawKey)); err != nil {
+		return nil, ErrInvalidCredentials
+	}
+
+	scopes, err := key.ScopesList()
+	if err != nil {
+		return nil, err
+	}
+	return &models.Principal{
+		Subject:    key.Owner,
+		Scopes:     scopes,
+		AuthMethod: "api_key",
+	}, nil
+}
+
+// CreateAPIKey generates a new random key, stores its bcrypt hash and
+// lookup prefix, and returns the only copy of the plaintext the caller
+// will ever see.
+func (s *AuthService) CreateAPIKey(req models.CreateAPIKeyRequest) (*models.APIKeyResponse, error) {
+	rawKey, err := generateAPIKey()
+	if err != nil {
+		return nil, err
+	}
+	return s.storeAPIKey(rawKey, req.Owner, req.Scopes)
+}
+
+// RotateAPIKey revokes id and issues a fresh key with the same owner and
+// scopes, so a compromised key can be replaced without losing its grants.
+func (s *AuthService) RotateAPIKey(id uuid.UUID) (*models.APIKeyResponse, error) {
+	var key models.APIKey
+	if err := s.db.First(&key, id).Error; err != nil {
+		return nil, err
+	}
+	scopes, err := key.ScopesList()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.RevokeAPIKey(id); err != nil {
+		return nil, err
+	}
+
+	rawKey, err := generateAPIKey()
+	if err != nil {
+		return nil, err
+	}
+	return s.storeAPIKey(rawKey, key.Owner, scopes)
+}
+
+// RevokeAPIKey marks id unusable; AuthenticateAPIKey rejects it from then on.
+func (s *AuthService) RevokeAPIKey(id uuid.UUID) error {
+	now := time.Now()
+	return s.db.Model(&models.APIKey{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"revoked":    true,
+		"revoked_at": now,
+	}).Error
+}
+
+// ListAPIKeys returns every key (hashes are never serialized; see
+// models.APIKey's json tag on Hash).
+func (s *AuthService) ListAPIKeys() ([]models.APIKey, error) {
+	var keys []models.APIKey
+	if err := s.db.Order("created_at DESC").Find(&keys).Error; err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// SetAPIKeyLimit creates or updates the rate-limit override
+// middleware.RateLimiter applies to id's key, keyed by the key's Prefix
+// since the plaintext value needed to key it directly is never retained.
+func (s *AuthService) SetAPIKeyLimit(id uuid.UUID, rps float64, burst int) (*models.APIKeyLimit, error) {
+	var key models.APIKey
+	if err := s.db.First(&key, id).Error; err != nil {
+		return nil, err
+	}
+
+	var limit models.APIKeyLimit
+	if err := s.db.Where(models.APIKeyLimit{KeyPrefix: key.Prefix}).
+		Assign(models.APIKeyLimit{RequestsPerSecond: rps, Burst: burst}).
+		FirstOrCreate(&limit).Error; err != nil {
+		return nil, err
+	}
+	return &limit, nil
+}
+
+// DeleteAPIKeyLimit removes id's rate-limit override, if any, so its
+// requests fall back to the config-file default again.
+func (s *AuthService) DeleteAPIKeyLimit(id uuid.UUID) error {
+	var key models.APIKey
+	if err := s.db.First(&key, id).Error; err != nil {
+		return err
+	}
+	return s.db.Where("key_prefix = ?", key.Prefix).Delete(&models.APIKeyLimit{}).Error
+}
+
+func (s *AuthService) storeAPIKey(rawKey, owner string, scopes []string) (*models.APIKeyResponse, error) {
+	scopesJSON, err := json.Marshal(scopes)
+	if err != nil {
+		return nil, err
+	}
+
+	key := models.APIKey{
+		Prefix: rawKey[:models.APIKeyPrefixLen],
+		Hash:   "",
+		Owner:  owner,
+		Scopes: string(scopesJSON),
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(rawKey), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+	key.Hash = string(hash)
+
+	if err := s.db.Create(&key).Error; err != nil {
+		return nil, err
+	}
+	return &models.APIKeyResponse{APIKey: key, Key: rawKey}, nil
+}
+
+// generateAPIKey returns a random "sk_<40 hex chars>" plaintext key.
+func generateAPIKey() (string, error) {
+	buf := make([]byte, 20)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return "sk_" + hex.EncodeToString(buf), nil
+}
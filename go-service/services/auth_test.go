@@ -0,0 +1,144 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"fintech-ai-platform/go-service/config"
+)
+
+func newTestAuthService(t *testing.T) (*AuthService, sqlmock.Sqlmock) {
+	t.Helper()
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{
+		Conn:       sqlDB,
+		DriverName: "postgres",
+	}), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("gorm.Open: %v", err)
+	}
+
+	return &AuthService{
+		db: gormDB,
+		config: &config.Config{
+			Auth: config.AuthConfig{
+				JWTAlgorithm: "HS256",
+				JWTSecret:    "test-secret",
+				Issuer:       "test-issuer",
+			},
+		},
+	}, mock
+}
+
+func signTestJWT(t *testing.T, secret string, claims jwtClaims) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+	if err != nil {
+		t.Fatalf("sign test jwt: %v", err)
+	}
+	return token
+}
+
+func TestValidateJWT_Expired(t *testing.T) {
+	s, _ := newTestAuthService(t)
+	token := signTestJWT(t, s.config.Auth.JWTSecret, jwtClaims{
+		Scopes: []string{"workflow:read"},
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   "user-1",
+			Issuer:    s.config.Auth.Issuer,
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Hour)),
+		},
+	})
+
+	if _, err := s.ValidateJWT(token); err != ErrInvalidCredentials {
+		t.Fatalf("ValidateJWT(expired) = %v, want ErrInvalidCredentials", err)
+	}
+}
+
+func TestValidateJWT_InvalidSignature(t *testing.T) {
+	s, _ := newTestAuthService(t)
+	token := signTestJWT(t, "wrong-secret", jwtClaims{
+		Scopes: []string{"workflow:read"},
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   "user-1",
+			Issuer:    s.config.Auth.Issuer,
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	})
+
+	if _, err := s.ValidateJWT(token); err != ErrInvalidCredentials {
+		t.Fatalf("ValidateJWT(bad signature) = %v, want ErrInvalidCredentials", err)
+	}
+}
+
+func TestValidateJWT_Valid(t *testing.T) {
+	s, _ := newTestAuthService(t)
+	token := signTestJWT(t, s.config.Auth.JWTSecret, jwtClaims{
+		Scopes: []string{"workflow:read"},
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   "user-1",
+			Issuer:    s.config.Auth.Issuer,
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	})
+
+	principal, err := s.ValidateJWT(token)
+	if err != nil {
+		t.Fatalf("ValidateJWT(valid) returned error: %v", err)
+	}
+	if principal.Subject != "user-1" || !principal.HasScope("workflow:read") {
+		t.Fatalf("ValidateJWT(valid) = %+v, want subject user-1 with workflow:read", principal)
+	}
+}
+
+func TestAuthenticateAPIKey_Revoked(t *testing.T) {
+	s, mock := newTestAuthService(t)
+	rawKey := "sk_abcdef0123456789"
+	hash, err := bcrypt.GenerateFromPassword([]byte(rawKey), bcrypt.DefaultCost)
+	if err != nil {
+		t.Fatalf("bcrypt.GenerateFromPassword: %v", err)
+	}
+
+	rows := sqlmock.NewRows([]string{"id", "prefix", "hash", "owner", "scopes", "revoked"}).
+		AddRow(uuid.New(), rawKey[:8], string(hash), "owner-1", `["workflow:read"]`, true)
+	mock.ExpectQuery(`SELECT \* FROM "api_keys" WHERE prefix = \$1`).
+		WithArgs(rawKey[:8]).
+		WillReturnRows(rows)
+
+	if _, err := s.AuthenticateAPIKey(rawKey); err != ErrRevokedKey {
+		t.Fatalf("AuthenticateAPIKey(revoked) = %v, want ErrRevokedKey", err)
+	}
+}
+
+func TestAuthenticateAPIKey_Unknown(t *testing.T) {
+	s, mock := newTestAuthService(t)
+	rawKey := "sk_notregistered00000"
+
+	mock.ExpectQuery(`SELECT \* FROM "api_keys" WHERE prefix = \$1`).
+		WithArgs(rawKey[:8]).
+		WillReturnError(gorm.ErrRecordNotFound)
+
+	if _, err := s.AuthenticateAPIKey(rawKey); err != ErrInvalidCredentials {
+		t.Fatalf("AuthenticateAPIKey(unknown) = %v, want ErrInvalidCredentials", err)
+	}
+}
+
+func TestAuthenticateAPIKey_TooShort(t *testing.T) {
+	s, _ := newTestAuthService(t)
+
+	if _, err := s.AuthenticateAPIKey("short"); err != ErrInvalidCredentials {
+		t.Fatalf("AuthenticateAPIKey(short) = %v, want ErrInvalidCredentials", err)
+	}
+}
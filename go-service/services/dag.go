@@ -0,0 +1,172 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/google/uuid"
+
+	"fintech-ai-platform/go-service/models"
+)
+
+// ErrInvalidWorkflow indicates a workflow's step graph failed validation,
+// e.g. a step depends on a name that doesn't exist or the steps form a
+// dependency cycle.
+var ErrInvalidWorkflow = errors.New("invalid workflow step graph")
+
+// buildStepGraph indexes steps by name and resolves each step's DependsOn
+// names, rejecting references to steps that don't exist in the workflow.
+func buildStepGraph(steps []models.WorkflowStep) (map[string]*models.WorkflowStep, map[string][]string, error) {
+	byName := make(map[string]*models.WorkflowStep, len(steps))
+	for i := range steps {
+		byName[steps[i].Name] = &steps[i]
+	}
+
+	deps := make(map[string][]string, len(steps))
+	for i := range steps {
+		step := &steps[i]
+		dependsOn, err := step.DependsOnNames()
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %v", ErrInvalidWorkflow, err)
+		}
+		for _, dep := range dependsOn {
+			if _, ok := byName[dep]; !ok {
+				return nil, nil, fmt.Errorf("%w: step %q depends on unknown step %q", ErrInvalidWorkflow, step.Name, dep)
+			}
+		}
+		deps[step.Name] = dependsOn
+	}
+
+	return byName, deps, nil
+}
+
+// detectCycle runs a DFS over the dependency graph and fails if any step
+// transitively depends on itself.
+func detectCycle(deps map[string][]string) error {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	state := make(map[string]int, len(deps))
+
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch state[name] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("%w: cycle through step %q", ErrInvalidWorkflow, name)
+		}
+
+		state[name] = visiting
+		for _, dep := range deps[name] {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		state[name] = visited
+		return nil
+	}
+
+	for name := range deps {
+		if state[name] == unvisited {
+			if err := visit(name); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateWorkflowSteps rejects a step list that references unknown
+// dependencies or forms a cycle. It's run at CreateWorkflow/UpdateWorkflow
+// time so a broken graph never reaches the executor.
+func validateWorkflowSteps(steps []models.WorkflowStep) error {
+	_, deps, err := buildStepGraph(steps)
+	if err != nil {
+		return err
+	}
+	return detectCycle(deps)
+}
+
+// impliedLinearChain fills in a DependsOn for any request that didn't
+// specify one, making it depend on the previous step by Order. This keeps
+// simple workflows that never mention DependsOn behaving like the old
+// linear executor (run in Order, one at a time) instead of suddenly having
+// every step race in parallel the moment they're loaded as a DAG.
+func impliedLinearChain(reqs []models.CreateStepRequest) []models.CreateStepRequest {
+	ordered := make([]int, len(reqs))
+	for i := range ordered {
+		ordered[i] = i
+	}
+	sort.SliceStable(ordered, func(a, b int) bool {
+		return reqs[ordered[a]].Order < reqs[ordered[b]].Order
+	})
+
+	out := make([]models.CreateStepRequest, len(reqs))
+	copy(out, reqs)
+	for i, idx := range ordered {
+		if i == 0 || len(out[idx].DependsOn) > 0 {
+			continue
+		}
+		out[idx].DependsOn = []string{reqs[ordered[i-1]].Name}
+	}
+	return out
+}
+
+// stepsFromRequests converts a CreateWorkflowRequest's step payloads into
+// WorkflowStep models, JSON-encoding each step's DependsOn names so they can
+// be validated as a graph before anything is persisted. A step with no
+// DependsOn implicitly chains after the previous step by Order; see
+// impliedLinearChain.
+func stepsFromRequests(workflowID uuid.UUID, reqs []models.CreateStepRequest) ([]models.WorkflowStep, error) {
+	reqs = impliedLinearChain(reqs)
+	steps := make([]models.WorkflowStep, 0, len(reqs))
+	for _, r := range reqs {
+		dependsOn := ""
+		if len(r.DependsOn) > 0 {
+			b, err := json.Marshal(r.DependsOn)
+			if err != nil {
+				return nil, fmt.Errorf("step %s: invalid depends_on: %w", r.Name, err)
+			}
+			dependsOn = string(b)
+		}
+
+		steps = append(steps, models.WorkflowStep{
+			WorkflowID:  workflowID,
+			Name:        r.Name,
+			Description: r.Description,
+			Order:       r.Order,
+			ServiceType: r.ServiceType,
+			Config:      r.Config,
+			DependsOn:   dependsOn,
+		})
+	}
+	return steps, nil
+}
+
+// mergeStepInput layers a step's upstream dependency outputs (keyed by step
+// name) on top of the workflow's original execution input, so a step with
+// no DependsOn still sees exactly what the old linear executor gave it.
+func mergeStepInput(execInput map[string]interface{}, step *models.WorkflowStep, completed map[string]interface{}) map[string]interface{} {
+	dependsOn, _ := step.DependsOnNames()
+	if len(dependsOn) == 0 {
+		return execInput
+	}
+
+	merged := make(map[string]interface{}, len(execInput)+len(dependsOn))
+	for k, v := range execInput {
+		merged[k] = v
+	}
+	for _, dep := range dependsOn {
+		if result, ok := completed[dep]; ok {
+			merged[dep] = result
+		}
+	}
+	return merged
+}
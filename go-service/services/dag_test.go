@@ -0,0 +1,67 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+
+	"fintech-ai-platform/go-service/models"
+)
+
+func TestImpliedLinearChain(t *testing.T) {
+	reqs := []models.CreateStepRequest{
+		{Name: "fetch", Order: 1},
+		{Name: "transform", Order: 2},
+		{Name: "notify", Order: 3, DependsOn: []string{"fetch"}},
+	}
+
+	out := impliedLinearChain(reqs)
+
+	byName := make(map[string]models.CreateStepRequest, len(out))
+	for _, r := range out {
+		byName[r.Name] = r
+	}
+
+	if deps := byName["fetch"].DependsOn; len(deps) != 0 {
+		t.Errorf("first step by Order should have no implied dependency, got %v", deps)
+	}
+	if deps := byName["transform"].DependsOn; len(deps) != 1 || deps[0] != "fetch" {
+		t.Errorf("transform should implicitly depend on fetch, got %v", deps)
+	}
+	if deps := byName["notify"].DependsOn; len(deps) != 1 || deps[0] != "fetch" {
+		t.Errorf("notify's explicit depends_on should be left untouched, got %v", deps)
+	}
+}
+
+func TestStepsFromRequestsAppliesImplicitChain(t *testing.T) {
+	workflowID := uuid.New()
+	steps, err := stepsFromRequests(workflowID, []models.CreateStepRequest{
+		{Name: "a", Order: 1, ServiceType: "api"},
+		{Name: "b", Order: 2, ServiceType: "api"},
+	})
+	if err != nil {
+		t.Fatalf("stepsFromRequests: %v", err)
+	}
+
+	var b *models.WorkflowStep
+	for i := range steps {
+		if steps[i].Name == "b" {
+			b = &steps[i]
+		}
+	}
+	if b == nil {
+		t.Fatal("step b not found")
+	}
+
+	dependsOn, err := b.DependsOnNames()
+	if err != nil {
+		t.Fatalf("DependsOnNames: %v", err)
+	}
+	if len(dependsOn) != 1 || dependsOn[0] != "a" {
+		t.Errorf("expected b to implicitly depend on a, got %v", dependsOn)
+	}
+
+	if err := validateWorkflowSteps(steps); err != nil {
+		t.Errorf("implicitly-chained steps should form a valid graph: %v", err)
+	}
+}
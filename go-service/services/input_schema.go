@@ -0,0 +1,152 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"fintech-ai-platform/go-service/models"
+)
+
+// ErrDispatchInput indicates a manual dispatch request body failed
+// validation against a workflow's InputSchema.
+var ErrDispatchInput = errors.New("invalid dispatch input")
+
+// inputSchemaDoc is the subset of JSON Schema that inputSchemaFromRequests
+// compiles to and validateDispatchInput checks against: an object with named
+// properties, each restricted to a single JSON Schema primitive type.
+type inputSchemaDoc struct {
+	Type       string                   `json:"type"`
+	Properties map[string]inputProperty `json:"properties"`
+	Required   []string                 `json:"required,omitempty"`
+}
+
+type inputProperty struct {
+	Type        string      `json:"type"`
+	Description string      `json:"description,omitempty"`
+	Default     interface{} `json:"default,omitempty"`
+	Enum        []string    `json:"enum,omitempty"`
+}
+
+// inputSchemaFromRequests compiles a CreateWorkflowRequest's Inputs into a
+// JSON Schema document, stored on the Workflow and checked by
+// validateDispatchInput on every POST /workflows/{id}/dispatch call.
+func inputSchemaFromRequests(inputs []models.WorkflowInput) (string, error) {
+	if len(inputs) == 0 {
+		return "", nil
+	}
+
+	doc := inputSchemaDoc{
+		Type:       "object",
+		Properties: make(map[string]inputProperty, len(inputs)),
+	}
+	for _, in := range inputs {
+		doc.Properties[in.Name] = inputProperty{
+			Type:        in.Type,
+			Description: in.Description,
+			Default:     in.Default,
+			Enum:        in.Enum,
+		}
+		if in.Required {
+			doc.Required = append(doc.Required, in.Name)
+		}
+	}
+	sort.Strings(doc.Required)
+
+	b, err := json.Marshal(doc)
+	if err != nil {
+		return "", fmt.Errorf("compile input schema: %w", err)
+	}
+	return string(b), nil
+}
+
+// validateDispatchInput checks a dispatch request body against a workflow's
+// InputSchema, filling in any declared defaults for fields the caller
+// omitted. A workflow with no InputSchema accepts any input unchanged, same
+// as the pre-existing programmatic ExecuteWorkflow.
+func validateDispatchInput(schemaJSON string, values map[string]interface{}) (map[string]interface{}, error) {
+	if schemaJSON == "" {
+		return values, nil
+	}
+
+	var doc inputSchemaDoc
+	if err := json.Unmarshal([]byte(schemaJSON), &doc); err != nil {
+		return nil, fmt.Errorf("parse input schema: %w", err)
+	}
+
+	resolved := make(map[string]interface{}, len(values))
+	for k, v := range values {
+		resolved[k] = v
+	}
+
+	for name, prop := range doc.Properties {
+		if _, ok := resolved[name]; !ok && prop.Default != nil {
+			resolved[name] = prop.Default
+		}
+	}
+
+	var badFields []string
+	for _, name := range doc.Required {
+		if _, ok := resolved[name]; !ok {
+			badFields = append(badFields, name)
+		}
+	}
+	for name, value := range resolved {
+		prop, known := doc.Properties[name]
+		if !known {
+			continue
+		}
+		if !matchesJSONType(value, prop.Type) {
+			badFields = append(badFields, name)
+			continue
+		}
+		if len(prop.Enum) > 0 && !containsString(prop.Enum, fmt.Sprintf("%v", value)) {
+			badFields = append(badFields, name)
+		}
+	}
+
+	if len(badFields) > 0 {
+		sort.Strings(badFields)
+		return nil, fmt.Errorf("%w: %v", ErrDispatchInput, badFields)
+	}
+	return resolved, nil
+}
+
+// matchesJSONType reports whether value is a valid instance of a JSON
+// Schema primitive type, after unmarshaling through encoding/json's
+// map[string]interface{} representation (numbers always decode as
+// float64, regardless of whether the schema calls for number or integer).
+func matchesJSONType(value interface{}, schemaType string) bool {
+	switch schemaType {
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "integer":
+		f, ok := value.(float64)
+		return ok && f == float64(int64(f))
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "array":
+		_, ok := value.([]interface{})
+		return ok
+	case "object":
+		_, ok := value.(map[string]interface{})
+		return ok
+	default:
+		return true
+	}
+}
+
+func containsString(haystack []string, needle string) bool {
+	for _, s := range haystack {
+		if s == needle {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,58 @@
+package services
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"fintech-ai-platform/go-service/models"
+)
+
+// Orchestrator is the task/job surface handlers depend on. It exists
+// alongside *OrchestratorService so handler factories (and their tests) can
+// depend on the interface instead of the concrete type, and so a future
+// Postgres- or cache-backed implementation can be swapped in via the DI
+// container without touching a single handler.
+type Orchestrator interface {
+	CreateTask(req models.CreateTaskRequest) (*models.Task, error)
+	ListTasks(page, limit int) ([]models.Task, int64, error)
+	GetTask(id uuid.UUID) (*models.Task, error)
+	UpdateTask(id uuid.UUID, req models.CreateTaskRequest) (*models.Task, error)
+	DeleteTask(id uuid.UUID) error
+	ExecuteTask(ctx context.Context, id uuid.UUID, input map[string]interface{}) (map[string]interface{}, error)
+	StopTask(id uuid.UUID) error
+	ListTaskExecutions(taskID uuid.UUID, page, limit int) ([]models.TaskExecution, int64, error)
+	GetTaskExecution(id uuid.UUID) (*models.TaskExecution, error)
+	CurrentJobExecutionID(jobID uuid.UUID) (uuid.UUID, error)
+
+	CreateJob(req models.CreateJobRequest) (*models.Job, error)
+	ListJobs(page, limit int) ([]models.Job, int64, error)
+	GetJob(id uuid.UUID) (*models.Job, error)
+	UpdateJob(id uuid.UUID, req models.CreateJobRequest) (*models.Job, error)
+	DeleteJob(id uuid.UUID) error
+	StartJob(id uuid.UUID) error
+	StopJob(id uuid.UUID) error
+	ListJobExecutions(jobID uuid.UUID, page, limit int) ([]models.JobExecution, int64, error)
+	ListDeadLetterJobs(page, limit int) ([]models.Job, int64, error)
+	RequeueDeadLetterJob(id uuid.UUID) error
+}
+
+// Workflow is the workflow surface handlers depend on, for the same reason
+// as Orchestrator above.
+type Workflow interface {
+	CreateWorkflow(req models.CreateWorkflowRequest) (*models.Workflow, error)
+	ListWorkflows(page, limit int) ([]models.Workflow, int64, error)
+	GetWorkflow(id uuid.UUID) (*models.Workflow, error)
+	UpdateWorkflow(id uuid.UUID, req models.CreateWorkflowRequest) (*models.Workflow, error)
+	DeleteWorkflow(id uuid.UUID) error
+	ExecuteWorkflow(ctx context.Context, id uuid.UUID, input map[string]interface{}) (map[string]interface{}, error)
+	DispatchWorkflow(ctx context.Context, id uuid.UUID, rawInput map[string]interface{}) (map[string]interface{}, error)
+	GetWorkflowExecution(id uuid.UUID) (*models.WorkflowExecution, error)
+	ListWorkflowExecutions(workflowID uuid.UUID, page, limit int) ([]models.WorkflowExecution, int64, error)
+	ListWorkflowExecutionSteps(execID uuid.UUID, page, limit int) ([]models.WorkflowStepExecution, int64, error)
+}
+
+var (
+	_ Orchestrator = (*OrchestratorService)(nil)
+	_ Workflow     = (*WorkflowService)(nil)
+)
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"fintech-ai-platform/go-service/config"
+	"fintech-ai-platform/go-service/models"
+)
+
+// maxLogLinesPerExecution bounds the Postgres ring buffer: once an
+// execution has logged more than this many lines, AppendLog trims the
+// oldest ones so the table can't grow unbounded for a long-running job.
+const maxLogLinesPerExecution = 1000
+
+// LogMessage is what AppendLog publishes to Redis and what a follower
+// receives from Subscribe. Terminal lets GET .../logs?follow=true close the
+// stream as soon as the execution it's following reaches a terminal
+// status, without polling the database on every line.
+type LogMessage struct {
+	Line     models.ExecutionLogLine `json:"line"`
+	Terminal bool                    `json:"terminal"`
+}
+
+// LogService backs the live log/progress streaming endpoints. The executor
+// calls AppendLog on every progress tick or step completion; handlers call
+// TailLogs for the initial backlog and Subscribe to follow new lines.
+// Postgres holds the ring-buffer backlog so a client that connects after
+// the fact still sees recent history; Redis pub/sub fans new lines out to
+// any number of followers without polling the database.
+type LogService struct {
+	db    *gorm.DB
+	redis *redis.Client
+}
+
+// NewLogService creates a LogService backed by the given Postgres handle
+// and a Redis client dialed from cfg.
+func NewLogService(db *gorm.DB, cfg *config.RedisConfig) *LogService {
+	return &LogService{
+		db: db,
+		redis: redis.NewClient(&redis.Options{
+			Addr:     fmt.Sprintf("%s:%s", cfg.Host, cfg.Port),
+			Password: cfg.Password,
+			DB:       cfg.DB,
+		}),
+	}
+}
+
+func logChannel(executionID uuid.UUID) string {
+	return "logs:" + executionID.String()
+}
+
+// AppendLog assigns the next sequence number for executionID, persists the
+// line to the Postgres ring buffer, trims the backlog to
+// maxLogLinesPerExecution, and publishes it to the execution's Redis
+// channel for any live followers. Set terminal on the line that closes out
+// the execution (success, failure, or cancellation) so followers know to
+// stop instead of waiting on a connection that will never produce more.
+//
+// Two concurrent steps of the same execution calling AppendLog at once
+// would otherwise both read the same MAX(seq) and insert duplicate/
+// out-of-order sequence numbers, so the transaction first takes a
+// Postgres advisory lock keyed on executionID: the second caller blocks
+// until the first commits, serializing the read-then-insert per execution
+// without a round trip taking a row lock on a table that may have no
+// rows yet.
+func (s *LogService) AppendLog(ctx context.Context, executionID uuid.UUID, message string, terminal bool) error {
+	var line models.ExecutionLogLine
+	if err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtextextended(?, 0))", executionID.String()).Error; err != nil {
+			return err
+		}
+
+		var lastSeq int
+		if err := tx.Model(&models.ExecutionLogLine{}).
+			Where("execution_id = ?", executionID).
+			Select("COALESCE(MAX(seq), 0)").
+			Scan(&lastSeq).Error; err != nil {
+			return err
+		}
+
+		line = models.ExecutionLogLine{
+			ExecutionID: executionID,
+			Seq:         lastSeq + 1,
+			Message:     message,
+		}
+		if err := tx.Create(&line).Error; err != nil {
+			return err
+		}
+
+		return tx.Where("execution_id = ? AND seq <= ?", executionID, line.Seq-maxLogLinesPerExecution).
+			Delete(&models.ExecutionLogLine{}).Error
+	}); err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(LogMessage{Line: line, Terminal: terminal})
+	if err != nil {
+		return err
+	}
+	return s.redis.Publish(ctx, logChannel(executionID), payload).Err()
+}
+
+// TailLogs returns an execution's last n ring-buffer lines, oldest first.
+func (s *LogService) TailLogs(executionID uuid.UUID, n int) ([]models.ExecutionLogLine, error) {
+	var lines []models.ExecutionLogLine
+	if err := s.db.Where("execution_id = ?", executionID).
+		Order("seq DESC").
+		Limit(n).
+		Find(&lines).Error; err != nil {
+		return nil, err
+	}
+	for i, j := 0, len(lines)-1; i < j; i, j = i+1, j-1 {
+		lines[i], lines[j] = lines[j], lines[i]
+	}
+	return lines, nil
+}
+
+// Subscribe returns a Redis pub/sub handle for an execution's log channel.
+// The caller must Close it (e.g. when the client disconnects).
+func (s *LogService) Subscribe(ctx context.Context, executionID uuid.UUID) *redis.PubSub {
+	return s.redis.Subscribe(ctx, logChannel(executionID))
+}
@@ -0,0 +1,68 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func newTestLogService(t *testing.T) (*LogService, sqlmock.Sqlmock) {
+	t.Helper()
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{
+		Conn:       sqlDB,
+		DriverName: "postgres",
+	}), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("gorm.Open: %v", err)
+	}
+
+	mr := miniredis.RunT(t)
+
+	return &LogService{
+		db:    gormDB,
+		redis: redis.NewClient(&redis.Options{Addr: mr.Addr()}),
+	}, mock
+}
+
+// TestAppendLogTakesAdvisoryLockBeforeReadingSeq guards the fix for the
+// read-then-insert race: two concurrent AppendLog calls for the same
+// execution must not both compute the same MAX(seq) and insert duplicate
+// sequence numbers. The advisory lock serializes them, so within a single
+// transaction it must be acquired before the seq is read.
+func TestAppendLogTakesAdvisoryLockBeforeReadingSeq(t *testing.T) {
+	s, mock := newTestLogService(t)
+	executionID := uuid.New()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`SELECT pg_advisory_xact_lock\(hashtextextended\(\$1, 0\)\)`).
+		WithArgs(executionID.String()).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT COALESCE\(MAX\(seq\), 0\) FROM "execution_log_lines" WHERE execution_id = \$1`).
+		WithArgs(executionID).
+		WillReturnRows(sqlmock.NewRows([]string{"coalesce"}).AddRow(4))
+	mock.ExpectQuery(`INSERT INTO "execution_log_lines"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(uuid.New(), nil))
+	mock.ExpectExec(`DELETE FROM "execution_log_lines"`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	if err := s.AppendLog(context.Background(), executionID, "step done", false); err != nil {
+		t.Fatalf("AppendLog: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations (lock must be taken before the seq read): %v", err)
+	}
+}
@@ -0,0 +1,29 @@
+package services
+
+import (
+	"go.uber.org/fx"
+	"gorm.io/gorm"
+
+	"fintech-ai-platform/go-service/config"
+)
+
+// Module assembles every service this package exports for the DI
+// container. Orchestrator and Workflow are bound to their interfaces via
+// fx.As, so the rest of the container (handlers included) only ever sees
+// services.Orchestrator / services.Workflow — swapping in a Postgres- or
+// cache-backed implementation later is a one-line change here, with no
+// handler left to touch.
+var Module = fx.Module("services",
+	fx.Provide(
+		fx.Annotate(NewOrchestratorService, fx.As(new(Orchestrator))),
+		fx.Annotate(NewWorkflowService, fx.As(new(Workflow))),
+		newLogService,
+		NewAuthService,
+	),
+)
+
+// newLogService adapts NewLogService's (db, *config.RedisConfig) signature
+// to fx's single-call convention.
+func newLogService(db *gorm.DB, cfg *config.Config) *LogService {
+	return NewLogService(db, &cfg.Redis)
+}
@@ -0,0 +1,58 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"go.uber.org/fx"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"fintech-ai-platform/go-service/config"
+)
+
+// TestModuleProvidesOrchestratorAndWorkflow wires Module up in isolation
+// (the same shape cmd/serve.go's fx.New call uses) and asserts it resolves
+// non-nil Orchestrator/Workflow implementations backed by the supplied
+// *gorm.DB, guarding against a provider silently leaving one of them
+// unwired.
+func TestModuleProvidesOrchestratorAndWorkflow(t *testing.T) {
+	sqlDB, _, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer sqlDB.Close()
+
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{
+		Conn:       sqlDB,
+		DriverName: "postgres",
+	}), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("gorm.Open: %v", err)
+	}
+
+	var (
+		orchestrator Orchestrator
+		workflow     Workflow
+		auth         *AuthService
+	)
+	app := fx.New(
+		fx.Supply(gormDB, &config.Config{}),
+		Module,
+		fx.Populate(&orchestrator, &workflow, &auth),
+		fx.NopLogger,
+	)
+	if err := app.Err(); err != nil {
+		t.Fatalf("fx.New: %v", err)
+	}
+
+	if orchestrator == nil {
+		t.Error("Module did not provide an Orchestrator")
+	}
+	if workflow == nil {
+		t.Error("Module did not provide a Workflow")
+	}
+	if auth == nil {
+		t.Error("Module did not provide an AuthService")
+	}
+}
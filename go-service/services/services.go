@@ -1,47 +1,107 @@
 package services
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"net/http"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
 	"gorm.io/gorm"
 
 	"fintech-ai-platform/go-service/config"
+	"fintech-ai-platform/go-service/metrics"
 	"fintech-ai-platform/go-service/models"
+	"fintech-ai-platform/go-service/pkg/runner"
 )
 
 // OrchestratorService handles task and job orchestration
 type OrchestratorService struct {
-	db     *gorm.DB
-	logger *logrus.Logger
-	config *config.Config
+	db         *gorm.DB
+	logger     *logrus.Logger
+	config     *config.Config
+	runner     *runner.Runner
+	executions *executionRegistry
+	logs       *LogService
 }
 
 // WorkflowService handles workflow management
 type WorkflowService struct {
-	db     *gorm.DB
-	logger *logrus.Logger
-	config *config.Config
+	db         *gorm.DB
+	logger     *logrus.Logger
+	config     *config.Config
+	runner     *runner.Runner
+	executions *executionRegistry
+	logs       *LogService
 }
 
-// NewOrchestratorService creates a new orchestrator service
-func NewOrchestratorService() *OrchestratorService {
-	return &OrchestratorService{
-		logger: logrus.New(),
-		config: config.LoadConfig(),
+// NewOrchestratorService creates a new orchestrator service backed by db.
+func NewOrchestratorService(db *gorm.DB) *OrchestratorService {
+	cfg := config.LoadConfig()
+	s := &OrchestratorService{
+		db:         db,
+		logger:     logrus.New(),
+		config:     cfg,
+		runner:     runner.New(cfg),
+		executions: newExecutionRegistry(),
 	}
+	s.logs = NewLogService(s.db, &cfg.Redis)
+	return s
 }
 
-// NewWorkflowService creates a new workflow service
-func NewWorkflowService() *WorkflowService {
-	return &WorkflowService{
-		logger: logrus.New(),
-		config: config.LoadConfig(),
+// NewWorkflowService creates a new workflow service backed by db.
+func NewWorkflowService(db *gorm.DB) *WorkflowService {
+	cfg := config.LoadConfig()
+	s := &WorkflowService{
+		db:         db,
+		logger:     logrus.New(),
+		config:     cfg,
+		runner:     runner.New(cfg),
+		executions: newExecutionRegistry(),
 	}
+	s.logs = NewLogService(s.db, &cfg.Redis)
+	return s
+}
+
+// executionRegistry tracks the context.CancelFunc for each in-flight
+// TaskExecution/WorkflowExecution, keyed by its ID, so StopTask can actually
+// abort the outbound HTTP call instead of only flipping a status flag.
+type executionRegistry struct {
+	mu      sync.Mutex
+	cancels map[uuid.UUID]context.CancelFunc
+}
+
+func newExecutionRegistry() *executionRegistry {
+	return &executionRegistry{cancels: make(map[uuid.UUID]context.CancelFunc)}
+}
+
+func (r *executionRegistry) register(id uuid.UUID, cancel context.CancelFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cancels[id] = cancel
+}
+
+func (r *executionRegistry) release(id uuid.UUID) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.cancels, id)
+}
+
+// cancel invokes and forgets the registered CancelFunc, if one is still
+// registered for id. It reports whether an in-flight execution was found.
+func (r *executionRegistry) cancel(id uuid.UUID) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cancel, ok := r.cancels[id]
+	if ok {
+		cancel()
+		delete(r.cancels, id)
+	}
+	return ok
 }
 
 // Task methods
@@ -106,49 +166,195 @@ func (s *OrchestratorService) DeleteTask(id uuid.UUID) error {
 	return s.db.Delete(&models.Task{}, id).Error
 }
 
-func (s *OrchestratorService) ExecuteTask(id uuid.UUID, input map[string]interface{}) (map[string]interface{}, error) {
+// ExecuteTask runs a task's configured action to completion or failure.
+// ctx bounds the whole attempt; StopTask cancels the derived context
+// registered for this execution, which aborts whatever HTTP call the
+// runner currently has in flight instead of leaving it to run to its
+// per-request timeout.
+func (s *OrchestratorService) ExecuteTask(ctx context.Context, id uuid.UUID, input map[string]interface{}) (map[string]interface{}, error) {
 	task, err := s.GetTask(id)
 	if err != nil {
 		return nil, err
 	}
 
-	// Update task status
+	// Start a new attempt: record it in TaskExecution rather than just
+	// overwriting the task's own Status/Result/Error, so prior attempts
+	// stay visible via GET /orchestration/tasks/{id}/executions.
 	now := time.Now()
-	task.Status = models.StatusRunning
-	task.StartedAt = &now
-	s.db.Save(task)
+	inputJSON, _ := json.Marshal(input)
+	execution := &models.TaskExecution{
+		TaskID:    task.ID,
+		Attempt:   task.TotalExecutions + 1,
+		Status:    models.StatusRunning,
+		Input:     string(inputJSON),
+		StartedAt: now,
+	}
+	if err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(execution).Error; err != nil {
+			return err
+		}
+		return tx.Model(task).Updates(map[string]interface{}{
+			"status":                 models.StatusRunning,
+			"started_at":             now,
+			"total_executions":       gorm.Expr("total_executions + 1"),
+			"in_progress_executions": gorm.Expr("in_progress_executions + 1"),
+		}).Error
+	}); err != nil {
+		return nil, err
+	}
+
+	execCtx, cancel := context.WithCancel(ctx)
+	s.executions.register(execution.ID, cancel)
+	defer s.executions.release(execution.ID)
+	defer cancel()
+
+	if err := s.logs.AppendLog(ctx, execution.ID, fmt.Sprintf("task started (attempt %d)", execution.Attempt), false); err != nil {
+		s.logger.WithError(err).Warn("failed to publish task start log line")
+	}
 
 	// Execute based on task type
 	var result map[string]interface{}
 	switch task.Type {
 	case models.TaskTypeMLPrediction:
-		result, err = s.executeMLPrediction(task, input)
+		result, err = s.runner.ExecuteMLPrediction(execCtx, task.Config, input)
 	case models.TaskTypeOCRExtraction:
-		result, err = s.executeOCRExtraction(task, input)
+		result, err = s.runner.ExecuteOCRExtraction(execCtx, task.Config, input)
 	case models.TaskTypeAPICall:
-		result, err = s.executeAPICall(task, input)
+		result, err = s.runner.ExecuteAPICall(execCtx, task.Config, input)
 	case models.TaskTypeWorkflow:
-		result, err = s.executeWorkflow(task, input)
+		result, err = s.executeWorkflow(execCtx, task, input)
 	default:
 		err = fmt.Errorf("unsupported task type: %s", task.Type)
 	}
 
-	// Update task with result
+	// Finalize the attempt and roll the outcome up onto the task.
 	completedAt := time.Now()
-	task.CompletedAt = &completedAt
+	execution.EndedAt = &completedAt
+	taskUpdates := map[string]interface{}{
+		"completed_at":           completedAt,
+		"in_progress_executions": gorm.Expr("in_progress_executions - 1"),
+	}
 	if err != nil {
-		task.Status = models.StatusFailed
-		task.Error = err.Error()
+		execution.Status = models.StatusFailed
+		execution.Error = err.Error()
+		taskUpdates["status"] = models.StatusFailed
+		taskUpdates["error"] = err.Error()
+		taskUpdates["failed_executions"] = gorm.Expr("failed_executions + 1")
 	} else {
-		task.Status = models.StatusCompleted
 		resultJSON, _ := json.Marshal(result)
-		task.Result = string(resultJSON)
+		execution.Status = models.StatusCompleted
+		execution.Result = string(resultJSON)
+		taskUpdates["status"] = models.StatusCompleted
+		taskUpdates["result"] = string(resultJSON)
+		taskUpdates["succeeded_executions"] = gorm.Expr("succeeded_executions + 1")
+	}
+
+	if txErr := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(execution).Error; err != nil {
+			return err
+		}
+		return tx.Model(task).Updates(taskUpdates).Error
+	}); txErr != nil {
+		return nil, txErr
+	}
+
+	logMsg := "task completed"
+	if err != nil {
+		logMsg = fmt.Sprintf("task failed: %v", err)
+	}
+	if logErr := s.logs.AppendLog(ctx, execution.ID, logMsg, true); logErr != nil {
+		s.logger.WithError(logErr).Warn("failed to publish task completion log line")
 	}
-	s.db.Save(task)
 
 	return result, err
 }
 
+// StopTask cancels a task's in-flight execution via the context.CancelFunc
+// registered for it when ExecuteTask started, then marks the open
+// TaskExecution and the task itself as cancelled.
+func (s *OrchestratorService) StopTask(id uuid.UUID) error {
+	now := time.Now()
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		var task models.Task
+		if err := tx.First(&task, id).Error; err != nil {
+			return err
+		}
+
+		var execution models.TaskExecution
+		err := tx.Where("task_id = ? AND ended_at IS NULL", id).Order("attempt DESC").First(&execution).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("task %s has no in-flight execution", id)
+		}
+		if err != nil {
+			return err
+		}
+
+		s.executions.cancel(execution.ID)
+
+		execution.Status = models.StatusCancelled
+		execution.EndedAt = &now
+		if err := tx.Save(&execution).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&task).Updates(map[string]interface{}{
+			"status":                 models.StatusCancelled,
+			"completed_at":           now,
+			"in_progress_executions": gorm.Expr("in_progress_executions - 1"),
+			"stopped_executions":     gorm.Expr("stopped_executions + 1"),
+		}).Error; err != nil {
+			return err
+		}
+
+		if logErr := s.logs.AppendLog(context.Background(), execution.ID, "task stopped", true); logErr != nil {
+			s.logger.WithError(logErr).Warn("failed to publish task stop log line")
+		}
+		return nil
+	})
+}
+
+// ListTaskExecutions returns a task's attempt history, most recent first.
+func (s *OrchestratorService) ListTaskExecutions(taskID uuid.UUID, page, limit int) ([]models.TaskExecution, int64, error) {
+	var executions []models.TaskExecution
+	var total int64
+
+	offset := (page - 1) * limit
+
+	if err := s.db.Model(&models.TaskExecution{}).Where("task_id = ?", taskID).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := s.db.Where("task_id = ?", taskID).Order("attempt DESC").Offset(offset).Limit(limit).Find(&executions).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return executions, total, nil
+}
+
+// GetTaskExecution fetches a single attempt by its TaskExecution id, used
+// by GET /orchestration/tasks/{id}/executions/{execId}/logs to resolve
+// which ring buffer and Redis channel to stream.
+func (s *OrchestratorService) GetTaskExecution(id uuid.UUID) (*models.TaskExecution, error) {
+	var execution models.TaskExecution
+	if err := s.db.First(&execution, id).Error; err != nil {
+		return nil, err
+	}
+	return &execution, nil
+}
+
+// CurrentJobExecutionID returns the JobExecution id for a job's most
+// recent attempt, used by GET /jobs/{id}/logs to resolve which execution's
+// log ring buffer and Redis channel to read (a job, unlike a task or
+// workflow, only ever has one attempt in flight at a time).
+func (s *OrchestratorService) CurrentJobExecutionID(jobID uuid.UUID) (uuid.UUID, error) {
+	var execution models.JobExecution
+	if err := s.db.Where("job_id = ?", jobID).Order("attempt DESC").First(&execution).Error; err != nil {
+		return uuid.Nil, err
+	}
+	return execution.ID, nil
+}
+
 // Job methods
 
 func (s *OrchestratorService) CreateJob(req models.CreateJobRequest) (*models.Job, error) {
@@ -211,46 +417,161 @@ func (s *OrchestratorService) DeleteJob(id uuid.UUID) error {
 	return s.db.Delete(&models.Job{}, id).Error
 }
 
+// StartJob confirms a job is queued and ready to run. It no longer executes
+// the job itself: a job stays in StatusPending (set at creation) until an
+// agent in the pool claims it via AgentServer.AcquireJob's long poll, which
+// is what lets job execution scale and survive independently of the API
+// server. See AgentServer and cmd/agent.
 func (s *OrchestratorService) StartJob(id uuid.UUID) error {
 	job, err := s.GetJob(id)
 	if err != nil {
 		return err
 	}
 
-	job.Status = models.StatusRunning
+	if job.Status != models.StatusPending {
+		return fmt.Errorf("job %s is not pending (status=%s)", id, job.Status)
+	}
+
+	return nil
+}
+
+// StopJob cancels a job and, if an agent currently holds its lease, closes
+// out the in-flight JobExecution so the attempt history doesn't have a
+// record stuck open forever. Unlike StopTask, this can't abort the agent's
+// in-flight HTTP call directly: job execution runs in a separate cmd/agent
+// process reached only through the gRPC agent pool, which has no cancel RPC
+// yet. The runner's per-attempt timeout (see pkg/runner) still bounds how
+// long a stopped job's goroutine can run.
+func (s *OrchestratorService) StopJob(id uuid.UUID) error {
 	now := time.Now()
-	job.StartedAt = &now
+	var stoppedExecutionID uuid.UUID
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var job models.Job
+		if err := tx.First(&job, id).Error; err != nil {
+			return err
+		}
+
+		wasRunning := job.Status == models.StatusRunning
+		job.Status = models.StatusCancelled
+		job.CompletedAt = &now
+		job.StoppedExecutions++
+		if wasRunning {
+			job.InProgressExecutions--
+		}
+		if err := tx.Save(&job).Error; err != nil {
+			return err
+		}
 
-	if err := s.db.Save(job).Error; err != nil {
+		if !wasRunning {
+			return nil
+		}
+
+		var execution models.JobExecution
+		err := tx.Where("job_id = ? AND ended_at IS NULL", id).Order("attempt DESC").First(&execution).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		execution.Status = models.StatusCancelled
+		execution.EndedAt = &now
+		stoppedExecutionID = execution.ID
+		return tx.Save(&execution).Error
+	})
+	if err != nil {
 		return err
 	}
 
-	// Start job execution in background
-	go s.executeJob(job)
+	if stoppedExecutionID != uuid.Nil {
+		if logErr := s.logs.AppendLog(context.Background(), stoppedExecutionID, "job stopped", true); logErr != nil {
+			s.logger.WithError(logErr).Warn("failed to publish job stop log line")
+		}
+	}
+	return nil
+}
+
+// ListDeadLetterJobs returns jobs that exhausted AgentServer's retry budget
+// (see AgentConfig.MaxAttempts) and are waiting on an operator to inspect
+// and RequeueDeadLetterJob them, for GET /jobs/dead-letter.
+func (s *OrchestratorService) ListDeadLetterJobs(page, limit int) ([]models.Job, int64, error) {
+	var jobs []models.Job
+	var total int64
+
+	offset := (page - 1) * limit
+
+	if err := s.db.Model(&models.Job{}).Where("status = ?", models.StatusDeadLetter).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := s.db.Where("status = ?", models.StatusDeadLetter).Offset(offset).Limit(limit).Find(&jobs).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return jobs, total, nil
+}
 
+// RequeueDeadLetterJob resets a dead-lettered job back to StatusPending with
+// a fresh attempt budget, for the admin-only POST /jobs/{id}/requeue. It
+// errors if the job isn't currently dead-lettered.
+func (s *OrchestratorService) RequeueDeadLetterJob(id uuid.UUID) error {
+	res := s.db.Model(&models.Job{}).
+		Where("id = ? AND status = ?", id, models.StatusDeadLetter).
+		Updates(map[string]interface{}{
+			"status":           models.StatusPending,
+			"error":            "",
+			"next_retry_at":    nil,
+			"total_executions": 0,
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return fmt.Errorf("job %s is not dead-lettered", id)
+	}
 	return nil
 }
 
-func (s *OrchestratorService) StopJob(id uuid.UUID) error {
-	job, err := s.GetJob(id)
-	if err != nil {
-		return err
+// ListJobExecutions returns a job's attempt history, most recent first.
+func (s *OrchestratorService) ListJobExecutions(jobID uuid.UUID, page, limit int) ([]models.JobExecution, int64, error) {
+	var executions []models.JobExecution
+	var total int64
+
+	offset := (page - 1) * limit
+
+	if err := s.db.Model(&models.JobExecution{}).Where("job_id = ?", jobID).Count(&total).Error; err != nil {
+		return nil, 0, err
 	}
 
-	job.Status = models.StatusCancelled
-	now := time.Now()
-	job.CompletedAt = &now
+	if err := s.db.Where("job_id = ?", jobID).Order("attempt DESC").Offset(offset).Limit(limit).Find(&executions).Error; err != nil {
+		return nil, 0, err
+	}
 
-	return s.db.Save(job).Error
+	return executions, total, nil
 }
 
 // Workflow methods
 
 func (s *WorkflowService) CreateWorkflow(req models.CreateWorkflowRequest) (*models.Workflow, error) {
+	steps, err := stepsFromRequests(uuid.Nil, req.Steps)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateWorkflowSteps(steps); err != nil {
+		return nil, err
+	}
+	inputSchema, err := inputSchemaFromRequests(req.Inputs)
+	if err != nil {
+		return nil, err
+	}
+
 	workflow := &models.Workflow{
 		Name:        req.Name,
 		Description: req.Description,
 		Status:      "draft",
+		InputSchema: inputSchema,
 	}
 
 	if err := s.db.Create(workflow).Error; err != nil {
@@ -258,16 +579,9 @@ func (s *WorkflowService) CreateWorkflow(req models.CreateWorkflowRequest) (*mod
 	}
 
 	// Create workflow steps
-	for _, stepReq := range req.Steps {
-		step := &models.WorkflowStep{
-			WorkflowID:  workflow.ID,
-			Name:        stepReq.Name,
-			Description: stepReq.Description,
-			Order:       stepReq.Order,
-			ServiceType: stepReq.ServiceType,
-			Config:      stepReq.Config,
-		}
-		if err := s.db.Create(step).Error; err != nil {
+	for i := range steps {
+		steps[i].WorkflowID = workflow.ID
+		if err := s.db.Create(&steps[i]).Error; err != nil {
 			return nil, err
 		}
 	}
@@ -301,9 +615,22 @@ func (s *WorkflowService) GetWorkflow(id uuid.UUID) (*models.Workflow, error) {
 }
 
 func (s *WorkflowService) UpdateWorkflow(id uuid.UUID, req models.CreateWorkflowRequest) (*models.Workflow, error) {
+	steps, err := stepsFromRequests(id, req.Steps)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateWorkflowSteps(steps); err != nil {
+		return nil, err
+	}
+	inputSchema, err := inputSchemaFromRequests(req.Inputs)
+	if err != nil {
+		return nil, err
+	}
+
 	workflow := &models.Workflow{
 		Name:        req.Name,
 		Description: req.Description,
+		InputSchema: inputSchema,
 	}
 
 	if err := s.db.Model(&models.Workflow{}).Where("id = ?", id).Updates(workflow).Error; err != nil {
@@ -314,16 +641,8 @@ func (s *WorkflowService) UpdateWorkflow(id uuid.UUID, req models.CreateWorkflow
 	s.db.Where("workflow_id = ?", id).Delete(&models.WorkflowStep{})
 
 	// Create new steps
-	for _, stepReq := range req.Steps {
-		step := &models.WorkflowStep{
-			WorkflowID:  id,
-			Name:        stepReq.Name,
-			Description: stepReq.Description,
-			Order:       stepReq.Order,
-			ServiceType: stepReq.ServiceType,
-			Config:      stepReq.Config,
-		}
-		if err := s.db.Create(step).Error; err != nil {
+	for i := range steps {
+		if err := s.db.Create(&steps[i]).Error; err != nil {
 			return nil, err
 		}
 	}
@@ -341,215 +660,270 @@ func (s *WorkflowService) DeleteWorkflow(id uuid.UUID) error {
 	return s.db.Delete(&models.Workflow{}, id).Error
 }
 
-func (s *WorkflowService) ExecuteWorkflow(id uuid.UUID, input map[string]interface{}) (map[string]interface{}, error) {
+// ExecuteWorkflow runs a workflow's steps as a DAG: steps with no unfinished
+// dependencies run concurrently, and each step's output is merged into a
+// `steps.<name>` namespace that downstream steps can reference in their
+// config via `{{ steps.name.field }}` templates. It fails fast if any step
+// errors, cancelling the remaining in-flight steps. ctx bounds the whole
+// run and is what a caller (e.g. a request timeout or client disconnect)
+// uses to abort every step's in-flight HTTP call at once.
+func (s *WorkflowService) ExecuteWorkflow(ctx context.Context, id uuid.UUID, input map[string]interface{}) (map[string]interface{}, error) {
 	workflow, err := s.GetWorkflow(id)
 	if err != nil {
 		return nil, err
 	}
 
-	result := make(map[string]interface{})
-	currentInput := input
-
-	// Execute steps in order
-	for _, step := range workflow.Steps {
-		stepResult, err := s.executeWorkflowStep(step, currentInput)
-		if err != nil {
-			return nil, fmt.Errorf("step %s failed: %w", step.Name, err)
-		}
-
-		result[step.Name] = stepResult
-		currentInput = stepResult
-	}
-
-	return result, nil
-}
-
-// Helper methods
-
-func (s *OrchestratorService) executeMLPrediction(task *models.Task, input map[string]interface{}) (map[string]interface{}, error) {
-	// Call ML service
-	url := fmt.Sprintf("%s/api/v1/predictions/predict", s.config.Services.MLService)
-
-	requestBody := map[string]interface{}{
-		"model_type": "document_classification",
-		"input_data": input,
-	}
-
-	resp, err := http.Post(url, "application/json", nil)
+	byName, deps, err := buildStepGraph(workflow.Steps)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
-
-	var result map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+	if err := detectCycle(deps); err != nil {
 		return nil, err
 	}
 
-	return result, nil
-}
-
-func (s *OrchestratorService) executeOCRExtraction(task *models.Task, input map[string]interface{}) (map[string]interface{}, error) {
-	// Call OCR service
-	url := fmt.Sprintf("%s/api/v1/ocr/extract", s.config.Services.OCRService)
-
-	resp, err := http.Post(url, "application/json", nil)
-	if err != nil {
+	now := time.Now()
+	inputJSON, _ := json.Marshal(input)
+	execution := &models.WorkflowExecution{
+		WorkflowID: workflow.ID,
+		Attempt:    workflow.TotalExecutions + 1,
+		Status:     models.StatusRunning,
+		Input:      string(inputJSON),
+		StartedAt:  now,
+	}
+	if err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(execution).Error; err != nil {
+			return err
+		}
+		return tx.Model(workflow).Updates(map[string]interface{}{
+			"total_executions":       gorm.Expr("total_executions + 1"),
+			"in_progress_executions": gorm.Expr("in_progress_executions + 1"),
+		}).Error
+	}); err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
 
-	var result map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, err
+	execCtx, cancelExec := context.WithCancel(ctx)
+	s.executions.register(execution.ID, cancelExec)
+	defer s.executions.release(execution.ID)
+	defer cancelExec()
+
+	var (
+		mu        sync.Mutex
+		completed = make(map[string]interface{}, len(byName))
+		done      = make(map[string]chan struct{}, len(byName))
+	)
+	for name := range byName {
+		done[name] = make(chan struct{})
+	}
+
+	g, gctx := errgroup.WithContext(execCtx)
+	for name := range byName {
+		name := name
+		g.Go(func() error {
+			defer close(done[name])
+
+			for _, dep := range deps[name] {
+				select {
+				case <-done[dep]:
+				case <-gctx.Done():
+					return gctx.Err()
+				}
+			}
+
+			mu.Lock()
+			stepInput := mergeStepInput(input, byName[name], completed)
+			resolvedStep := *byName[name]
+			resolvedStep.Config = resolveTemplates(resolvedStep.Config, map[string]map[string]interface{}{
+				"inputs": input,
+				"steps":  completed,
+			})
+			mu.Unlock()
+
+			stepInputJSON, _ := json.Marshal(stepInput)
+			stepStarted := time.Now()
+			stepResult, stepErr := s.executeWorkflowStep(gctx, &resolvedStep, stepInput)
+			stepEnded := time.Now()
+
+			stepExecution := &models.WorkflowStepExecution{
+				WorkflowExecutionID: execution.ID,
+				StepName:            name,
+				Input:               string(stepInputJSON),
+				StartedAt:           stepStarted,
+				EndedAt:             &stepEnded,
+			}
+			if stepErr != nil {
+				stepExecution.Status = models.StatusFailed
+				stepExecution.Error = stepErr.Error()
+			} else {
+				resultJSON, _ := json.Marshal(stepResult)
+				stepExecution.Status = models.StatusCompleted
+				stepExecution.Result = string(resultJSON)
+			}
+			s.db.Create(stepExecution)
+
+			stepLogMsg := fmt.Sprintf("step %s completed", name)
+			if stepErr != nil {
+				stepLogMsg = fmt.Sprintf("step %s failed: %v", name, stepErr)
+			}
+			if logErr := s.logs.AppendLog(ctx, execution.ID, stepLogMsg, false); logErr != nil {
+				s.logger.WithError(logErr).Warn("failed to publish workflow step log line")
+			}
+
+			if stepErr != nil {
+				return fmt.Errorf("step %s failed: %w", name, stepErr)
+			}
+
+			mu.Lock()
+			completed[name] = stepResult
+			mu.Unlock()
+			return nil
+		})
+	}
+
+	runErr := g.Wait()
+
+	endedAt := time.Now()
+	execution.EndedAt = &endedAt
+	workflowUpdates := map[string]interface{}{
+		"in_progress_executions": gorm.Expr("in_progress_executions - 1"),
+	}
+	if runErr != nil {
+		execution.Status = models.StatusFailed
+		execution.Error = runErr.Error()
+		workflowUpdates["failed_executions"] = gorm.Expr("failed_executions + 1")
+	} else {
+		resultJSON, _ := json.Marshal(completed)
+		execution.Status = models.StatusCompleted
+		execution.Result = string(resultJSON)
+		workflowUpdates["succeeded_executions"] = gorm.Expr("succeeded_executions + 1")
 	}
 
-	return result, nil
-}
-
-func (s *OrchestratorService) executeAPICall(task *models.Task, input map[string]interface{}) (map[string]interface{}, error) {
-	// Execute API call based on task config
-	var config map[string]interface{}
-	if err := json.Unmarshal([]byte(task.Config), &config); err != nil {
-		return nil, err
+	if txErr := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(execution).Error; err != nil {
+			return err
+		}
+		return tx.Model(workflow).Updates(workflowUpdates).Error
+	}); txErr != nil {
+		return nil, txErr
 	}
 
-	url := config["url"].(string)
-	method := config["method"].(string)
-
-	req, err := http.NewRequest(method, url, nil)
-	if err != nil {
-		return nil, err
+	workflowLogMsg := "workflow completed"
+	if runErr != nil {
+		workflowLogMsg = fmt.Sprintf("workflow failed: %v", runErr)
 	}
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, err
+	if logErr := s.logs.AppendLog(ctx, execution.ID, workflowLogMsg, true); logErr != nil {
+		s.logger.WithError(logErr).Warn("failed to publish workflow completion log line")
 	}
-	defer resp.Body.Close()
+	metrics.WorkflowExecutionsTotal.WithLabelValues(execution.Status).Inc()
 
-	var result map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, err
+	if runErr != nil {
+		return nil, runErr
 	}
-
-	return result, nil
+	return completed, nil
 }
 
-func (s *OrchestratorService) executeWorkflow(task *models.Task, input map[string]interface{}) (map[string]interface{}, error) {
-	// Execute workflow based on task config
-	var config map[string]interface{}
-	if err := json.Unmarshal([]byte(task.Config), &config); err != nil {
+// DispatchWorkflow is the manual-trigger entry point for
+// POST /workflows/{id}/dispatch: it validates rawInput against the
+// workflow's InputSchema (filling in any declared defaults), then runs
+// ExecuteWorkflow with the resolved input as the initial `{{ inputs.foo }}`
+// namespace. Returns ErrDispatchInput if validation fails, listing the
+// offending field names.
+func (s *WorkflowService) DispatchWorkflow(ctx context.Context, id uuid.UUID, rawInput map[string]interface{}) (map[string]interface{}, error) {
+	workflow, err := s.GetWorkflow(id)
+	if err != nil {
 		return nil, err
 	}
 
-	workflowID := config["workflow_id"].(string)
-	workflowUUID, err := uuid.Parse(workflowID)
+	resolvedInput, err := validateDispatchInput(workflow.InputSchema, rawInput)
 	if err != nil {
 		return nil, err
 	}
 
-	workflowService := NewWorkflowService()
-	return workflowService.ExecuteWorkflow(workflowUUID, input)
-}
-
-func (s *OrchestratorService) executeJob(job *models.Job) {
-	// Execute job logic here
-	// This is a simplified implementation
-	for i := 0; i <= 100; i += 10 {
-		job.Progress = i
-		s.db.Save(job)
-		time.Sleep(1 * time.Second)
-	}
-
-	job.Status = models.StatusCompleted
-	job.Progress = 100
-	now := time.Now()
-	job.CompletedAt = &now
-	s.db.Save(job)
+	return s.ExecuteWorkflow(ctx, id, resolvedInput)
 }
 
-func (s *WorkflowService) executeWorkflowStep(step *models.WorkflowStep, input map[string]interface{}) (map[string]interface{}, error) {
-	switch step.ServiceType {
-	case "ml":
-		return s.executeMLStep(step, input)
-	case "ocr":
-		return s.executeOCRStep(step, input)
-	case "api":
-		return s.executeAPIStep(step, input)
-	default:
-		return input, nil
+// GetWorkflowExecution fetches a single attempt by its WorkflowExecution
+// id, used by GET /workflows/{id}/executions/{execId}/logs to resolve
+// which ring buffer and Redis channel to stream.
+func (s *WorkflowService) GetWorkflowExecution(id uuid.UUID) (*models.WorkflowExecution, error) {
+	var execution models.WorkflowExecution
+	if err := s.db.First(&execution, id).Error; err != nil {
+		return nil, err
 	}
+	return &execution, nil
 }
 
-func (s *WorkflowService) executeMLStep(step *models.WorkflowStep, input map[string]interface{}) (map[string]interface{}, error) {
-	// Call ML service
-	url := fmt.Sprintf("%s/api/v1/predictions/predict", s.config.Services.MLService)
+// ListWorkflowExecutions returns a workflow's attempt history, most recent first.
+func (s *WorkflowService) ListWorkflowExecutions(workflowID uuid.UUID, page, limit int) ([]models.WorkflowExecution, int64, error) {
+	var executions []models.WorkflowExecution
+	var total int64
 
-	requestBody := map[string]interface{}{
-		"model_type": "document_classification",
-		"input_data": input,
-	}
+	offset := (page - 1) * limit
 
-	resp, err := http.Post(url, "application/json", nil)
-	if err != nil {
-		return nil, err
+	if err := s.db.Model(&models.WorkflowExecution{}).Where("workflow_id = ?", workflowID).Count(&total).Error; err != nil {
+		return nil, 0, err
 	}
-	defer resp.Body.Close()
 
-	var result map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, err
+	if err := s.db.Where("workflow_id = ?", workflowID).Order("attempt DESC").Offset(offset).Limit(limit).Find(&executions).Error; err != nil {
+		return nil, 0, err
 	}
 
-	return result, nil
+	return executions, total, nil
 }
 
-func (s *WorkflowService) executeOCRStep(step *models.WorkflowStep, input map[string]interface{}) (map[string]interface{}, error) {
-	// Call OCR service
-	url := fmt.Sprintf("%s/api/v1/ocr/extract", s.config.Services.OCRService)
+// ListWorkflowExecutionSteps returns the per-step results recorded for a
+// single WorkflowExecution, in the order the steps finished.
+func (s *WorkflowService) ListWorkflowExecutionSteps(execID uuid.UUID, page, limit int) ([]models.WorkflowStepExecution, int64, error) {
+	var steps []models.WorkflowStepExecution
+	var total int64
+
+	offset := (page - 1) * limit
 
-	resp, err := http.Post(url, "application/json", nil)
-	if err != nil {
-		return nil, err
+	if err := s.db.Model(&models.WorkflowStepExecution{}).Where("workflow_execution_id = ?", execID).Count(&total).Error; err != nil {
+		return nil, 0, err
 	}
-	defer resp.Body.Close()
 
-	var result map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, err
+	if err := s.db.Where("workflow_execution_id = ?", execID).Order("ended_at").Offset(offset).Limit(limit).Find(&steps).Error; err != nil {
+		return nil, 0, err
 	}
 
-	return result, nil
+	return steps, total, nil
 }
 
-func (s *WorkflowService) executeAPIStep(step *models.WorkflowStep, input map[string]interface{}) (map[string]interface{}, error) {
-	// Execute API call based on step config
+// Helper methods
+
+func (s *OrchestratorService) executeWorkflow(ctx context.Context, task *models.Task, input map[string]interface{}) (map[string]interface{}, error) {
+	// Execute workflow based on task config
 	var config map[string]interface{}
-	if err := json.Unmarshal([]byte(step.Config), &config); err != nil {
+	if err := json.Unmarshal([]byte(task.Config), &config); err != nil {
 		return nil, err
 	}
 
-	url := config["url"].(string)
-	method := config["method"].(string)
-
-	req, err := http.NewRequest(method, url, nil)
+	workflowID := config["workflow_id"].(string)
+	workflowUUID, err := uuid.Parse(workflowID)
 	if err != nil {
 		return nil, err
 	}
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
+	workflowService := NewWorkflowService(s.db)
+	return workflowService.ExecuteWorkflow(ctx, workflowUUID, input)
+}
 
-	var result map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, err
+// executeWorkflowStep dispatches a step to the shared runner by its
+// ServiceType. The actual ML/OCR/API implementations live in pkg/runner so
+// cmd/agent can run the same code against jobs pulled off the queue. ctx
+// bounds this step's HTTP call on top of the per-attempt timeout the runner
+// derives from step.Config, so cancelling the workflow execution aborts it
+// immediately instead of waiting out the timeout.
+func (s *WorkflowService) executeWorkflowStep(ctx context.Context, step *models.WorkflowStep, input map[string]interface{}) (map[string]interface{}, error) {
+	switch step.ServiceType {
+	case "ml":
+		return s.runner.ExecuteMLPrediction(ctx, step.Config, input)
+	case "ocr":
+		return s.runner.ExecuteOCRExtraction(ctx, step.Config, input)
+	case "api":
+		return s.runner.ExecuteAPICall(ctx, step.Config, input)
+	default:
+		return input, nil
 	}
-
-	return result, nil
 }
@@ -0,0 +1,51 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func newTestOrchestratorService(t *testing.T) (*OrchestratorService, sqlmock.Sqlmock) {
+	t.Helper()
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{
+		Conn:       sqlDB,
+		DriverName: "postgres",
+	}), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("gorm.Open: %v", err)
+	}
+
+	return &OrchestratorService{db: gormDB}, mock
+}
+
+// TestCurrentJobExecutionID_LatestAttempt verifies it resolves to the most
+// recent attempt's execution id, not just any execution row for the job --
+// the per-attempt history means a job can have many.
+func TestCurrentJobExecutionID_LatestAttempt(t *testing.T) {
+	s, mock := newTestOrchestratorService(t)
+	jobID := uuid.New()
+	latestExecID := uuid.New()
+
+	mock.ExpectQuery(`SELECT \* FROM "job_executions" WHERE job_id = \$1 ORDER BY attempt DESC,"job_executions"."id" LIMIT 1`).
+		WithArgs(jobID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "job_id", "attempt"}).
+			AddRow(latestExecID, jobID, 3))
+
+	got, err := s.CurrentJobExecutionID(jobID)
+	if err != nil {
+		t.Fatalf("CurrentJobExecutionID: %v", err)
+	}
+	if got != latestExecID {
+		t.Errorf("CurrentJobExecutionID() = %v, want %v", got, latestExecID)
+	}
+}
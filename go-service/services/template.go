@@ -0,0 +1,70 @@
+package services
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+)
+
+// templateRef matches `{{ namespace.path.to.value }}` placeholders in a
+// step's raw JSON config.
+var templateRef = regexp.MustCompile(`\{\{\s*([\w.]+)\s*\}\}`)
+
+// resolveTemplates substitutes `{{ steps.name.field }}` / `{{ inputs.field }}`
+// references in a step's config with values looked up from namespaces, so a
+// downstream step can consume an upstream step's output by name.
+func resolveTemplates(config string, namespaces map[string]map[string]interface{}) string {
+	if config == "" {
+		return config
+	}
+
+	return templateRef.ReplaceAllStringFunc(config, func(match string) string {
+		ref := templateRef.FindStringSubmatch(match)[1]
+		parts := strings.Split(ref, ".")
+		if len(parts) < 2 {
+			return match
+		}
+
+		ns, ok := namespaces[parts[0]]
+		if !ok {
+			return match
+		}
+
+		value, ok := lookupPath(ns, parts[1:])
+		if !ok {
+			return match
+		}
+
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return match
+		}
+
+		// A string value substitutes its escaped contents into the quotes
+		// already surrounding the placeholder in config (e.g.
+		// `"url": "{{ steps.foo.url }}"`); any other JSON type substitutes
+		// in full, for placeholders used unquoted (e.g. `"amount": {{
+		// steps.foo.amount }}`).
+		if _, ok := value.(string); ok {
+			return string(encoded[1 : len(encoded)-1])
+		}
+		return string(encoded)
+	})
+}
+
+// lookupPath walks a dotted path through nested map[string]interface{}
+// values, as produced by unmarshaling a step's JSON result.
+func lookupPath(data map[string]interface{}, path []string) (interface{}, bool) {
+	var current interface{} = data
+	for _, key := range path {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		current, ok = m[key]
+		if !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
@@ -0,0 +1,48 @@
+package services
+
+import "testing"
+
+func TestResolveTemplatesEncodesValuesAsJSON(t *testing.T) {
+	namespaces := map[string]map[string]interface{}{
+		"steps": {
+			"fetch": map[string]interface{}{
+				"url":    `say "hi"`,
+				"amount": 42.5,
+				"meta":   map[string]interface{}{"ok": true},
+			},
+		},
+	}
+
+	config := `{"target":"{{ steps.fetch.url }}","amount":{{ steps.fetch.amount }},"meta":{{ steps.fetch.meta }}}`
+	resolved := resolveTemplates(config, namespaces)
+
+	want := `{"target":"say \"hi\"","amount":42.5,"meta":{"ok":true}}`
+	if resolved != want {
+		t.Errorf("resolveTemplates() = %q, want %q", resolved, want)
+	}
+}
+
+func TestResolveTemplatesInputsNamespace(t *testing.T) {
+	// ExecuteWorkflow seeds the "inputs" namespace from DispatchWorkflow's
+	// resolved input, matching the documented `{{ inputs.foo }}` syntax.
+	namespaces := map[string]map[string]interface{}{
+		"inputs": {"customer_id": "cust_123"},
+		"steps":  {},
+	}
+
+	config := `{"customer":"{{ inputs.customer_id }}"}`
+	resolved := resolveTemplates(config, namespaces)
+
+	want := `{"customer":"cust_123"}`
+	if resolved != want {
+		t.Errorf("resolveTemplates() = %q, want %q", resolved, want)
+	}
+}
+
+func TestResolveTemplatesLeavesUnknownRefsUntouched(t *testing.T) {
+	config := `{"target":"{{ steps.missing.url }}"}`
+	resolved := resolveTemplates(config, map[string]map[string]interface{}{"steps": {}})
+	if resolved != config {
+		t.Errorf("resolveTemplates() = %q, want unchanged %q", resolved, config)
+	}
+}